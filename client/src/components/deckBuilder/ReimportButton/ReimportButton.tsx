@@ -4,6 +4,7 @@ import {
     Box,
     Button,
     Dialog,
+    DialogActions,
     DialogContent,
     DialogTitle,
     LinearProgress,
@@ -29,6 +30,30 @@ const phaseLabels: Record<MTG_ImportPhase, string> = {
     [MTG_ImportPhase.REBUILDING_INDEX]: 'Rebuilding search index...',
     [MTG_ImportPhase.COMPLETE]: 'Import complete!',
     [MTG_ImportPhase.FAILED]: 'Import failed',
+    [MTG_ImportPhase.CANCELLED]: 'Import cancelled',
+}
+
+const formatMegabytes = (bytes: number) => `${(bytes / (1024 * 1024)).toFixed(0)} MB`
+
+const formatCounters = (status: MTG_ImportStatus) => {
+    const { counters } = status
+    switch (status.phase) {
+        case MTG_ImportPhase.FETCHING_CARDS:
+            if (counters.cardsProcessed > 0) {
+                return `${counters.cardsProcessed.toLocaleString()} cards read`
+            }
+            if (counters.bytesTotal > 0) {
+                return `${formatMegabytes(counters.bytesDownloaded)} / ${formatMegabytes(counters.bytesTotal)}`
+            }
+            return null
+        case MTG_ImportPhase.PROCESSING_CARDS:
+            if (counters.groupsTotal > 0) {
+                return `${counters.groupsProcessed.toLocaleString()} / ${counters.groupsTotal.toLocaleString()} cards grouped`
+            }
+            return null
+        default:
+            return null
+    }
 }
 
 export const ReimportButton = ({ onImportComplete }: ReimportButtonProps) => {
@@ -70,6 +95,13 @@ export const ReimportButton = ({ onImportComplete }: ReimportButtonProps) => {
                     message: result.error || 'Import failed',
                     severity: 'error',
                 })
+            } else if (result.phase === MTG_ImportPhase.CANCELLED) {
+                stopPolling()
+                setSnackbar({
+                    open: true,
+                    message: 'Import cancelled',
+                    severity: 'info',
+                })
             }
         } catch (err) {
             console.error('Failed to fetch import status:', err)
@@ -107,6 +139,17 @@ export const ReimportButton = ({ onImportComplete }: ReimportButtonProps) => {
         }
     }
 
+    const handleCancel = async () => {
+        try {
+            const result = await MTGFunctions.mutations.cancelMTGImportMutation()
+            if (!result.status) {
+                setError(result.message ?? 'Failed to cancel import')
+            }
+        } catch (err) {
+            setError(err instanceof Error ? err.message : 'Failed to cancel import')
+        }
+    }
+
     const handleClose = () => {
         setDialogOpen(false)
         stopPolling()
@@ -158,6 +201,12 @@ export const ReimportButton = ({ onImportComplete }: ReimportButtonProps) => {
                                     {status.progress}%
                                 </Typography>
                             </Box>
+                            {status.counters && formatCounters(status) && (
+                                <Typography variant="caption" color="text.secondary" sx={{ display: 'block', mt: 1 }}>
+                                    {formatCounters(status)}
+                                    {status.etaSeconds != null && ` · about ${Math.ceil(status.etaSeconds / 60)} min left`}
+                                </Typography>
+                            )}
                             {status.phase === MTG_ImportPhase.COMPLETE && (
                                 <Alert severity="success" sx={{ mt: 2 }}>
                                     Import completed successfully!
@@ -173,11 +222,23 @@ export const ReimportButton = ({ onImportComplete }: ReimportButtonProps) => {
                                     You can close this dialog. The import will continue in the background.
                                 </Typography>
                             )}
+                            {!isImporting && status.nextScheduledRun && (
+                                <Typography variant="caption" color="text.secondary" sx={{ display: 'block', mt: 2 }}>
+                                    Next scheduled import: {new Date(status.nextScheduledRun).toLocaleString()}
+                                </Typography>
+                            )}
                         </Box>
                     ) : (
                         <Typography>Starting import...</Typography>
                     )}
                 </DialogContent>
+                {isImporting && (
+                    <DialogActions>
+                        <Button color="error" onClick={() => void handleCancel()}>
+                            Cancel import
+                        </Button>
+                    </DialogActions>
+                )}
             </Dialog>
 
             <Snackbar
@@ -28,6 +28,8 @@ export const MTG_CardFragments = gql`
         layout
         manaCost
         name
+        defense
+        watermark
     }
 
     fragment MTG_CardVersionFragment on MTG_CardVersion {
@@ -46,6 +48,7 @@ export const MTG_CardFragments = gql`
         variation
         variationOf
         isDefault
+        defaultRank
         isAlchemy
         artist
         lang
@@ -53,6 +56,29 @@ export const MTG_CardFragments = gql`
         flavorText
         legalities
         games
+        borderColor
+        frame
+        watermark
+        securityStamp
+        textless
+        promo
+        digital
+        finishes
+        frameEffects
+        promoTypes
+        arenaID
+        mtgoID
+        tcgplayerID
+        cardmarketID
+        prices {
+            usd
+            usdFoil
+            usdEtched
+            eur
+            eurFoil
+            eurEtched
+            tix
+        }
         cardFaces {
             ...MTG_CardFaceFragment
         }
@@ -79,6 +105,10 @@ export const MTG_CardFragments = gql`
         keywords
         loyalty
         manaCost
+        defense
+        gameChanger
+        reserved
+        pennyRank
         tagAssignments {
             tag { ID name meta }
             chain { ID name meta }
@@ -99,6 +129,7 @@ export const MTG_DeckFragments = gql`
         name
         type
         autosave
+        printingProfileID
         cardFrontImage {
             cardID
             versionID
@@ -138,3 +169,20 @@ export const MTG_DeckFragments = gql`
     }
     ${MTG_CardFragments}
 `
+
+export const MTG_PrintingProfileFragments = gql`
+    fragment MTG_PrintingProfileFragment on MTG_PrintingProfile {
+        ID
+        name
+        isGlobal
+        rules {
+            kind
+            value
+            avoid
+        }
+        pins {
+            cardID
+            versionID
+        }
+    }
+`
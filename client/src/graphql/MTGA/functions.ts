@@ -4,22 +4,34 @@ import {
     MTG_AssignTagToCardInput,
     MTG_AssignTagToDeckInput,
     MTG_Card,
+    MTG_CatalogChange,
     MTG_CreateDeckInput,
     MTG_CreateFilterPresetInput,
+    MTG_CreatePrintingProfileInput,
     MTG_CreateTagInput,
     MTG_Deck,
     MTG_DeckDashboard,
+    MTG_DeckToken,
     MTG_DeleteFilterPresetInput,
+    MTG_DeletePrintingProfileInput,
     MTG_DeleteTagInput,
     MTG_Filter_Search,
     MTG_FilterPreset,
     MTG_ImportStatus,
+    MTG_LegalityChange,
+    MTG_PinCardVersionInput,
+    MTG_QuarantinedRecord,
+    MTG_PrintingProfile,
+    MTG_SetDeckPrintingProfileInput,
     MTG_Tag,
     MTG_TagAssignment,
+    MTG_Token_Search,
     MTG_UnassignTagFromCardInput,
     MTG_UnassignTagFromDeckInput,
+    MTG_UnpinCardVersionInput,
     MTG_UpdateDeckInput,
     MTG_UpdateFilterPresetInput,
+    MTG_UpdatePrintingProfileInput,
     MTG_UpdateTagInput,
     Mutation,
     MutationaddIgnoredCardArgs,
@@ -27,50 +39,76 @@ import {
     MutationassignTagToDeckArgs,
     MutationcreateMTGDeckArgs,
     MutationcreateMTGFilterPresetArgs,
+    MutationcreateMTGPrintingProfileArgs,
     MutationcreateMTGTagArgs,
     MutationdeleteMTGDeckArgs,
     MutationdeleteMTGFilterPresetArgs,
+    MutationdeleteMTGPrintingProfileArgs,
     MutationdeleteMTGTagArgs,
+    MutationpinMTGCardVersionArgs,
     MutationremoveIgnoredCardArgs,
+    MutationsetMTGDeckPrintingProfileArgs,
+    MutationsetMTGGlobalPrintingProfileArgs,
     MutationunassignTagFromCardArgs,
     MutationunassignTagFromDeckArgs,
+    MutationunpinMTGCardVersionArgs,
     MutationupdateMTGDeckArgs,
     MutationupdateMTGFilterPresetArgs,
+    MutationupdateMTGPrintingProfileArgs,
     MutationupdateMTGTagArgs,
     Query,
     QuerygetMTGCardsFilteredArgs,
+    QuerygetMTGCatalogChangesArgs,
     QuerygetMTGDeckArgs,
+    QuerygetMTGDeckTokensArgs,
     QuerygetMTGFilterPresetsArgs,
+    QuerygetMTGLegalityChangesArgs,
+    QuerygetMTGQuarantinedRecordsArgs,
     QuerygetMTGTagArgs,
+    QuerygetMTGTokensArgs,
     RemoveIgnoredCardInput,
     Response,
 } from '../types'
 import addIgnoredCard from './mutations/addIgnoredCard'
 import assignTagToCard from './mutations/assignTagToCard'
 import assignTagToDeck from './mutations/assignTagToDeck'
+import cancelMTGImport from './mutations/cancelMTGImport'
 import createMTGDeck from './mutations/createMTGDeck'
 import createMTGFilterPreset from './mutations/createMTGFilterPreset'
+import createMTGPrintingProfile from './mutations/createMTGPrintingProfile'
 import createMTGTag from './mutations/createMTGTag'
 import deleteMTGADeck from './mutations/deleteMTGDeck'
 import deleteMTGFilterPreset from './mutations/deleteMTGFilterPreset'
+import deleteMTGPrintingProfile from './mutations/deleteMTGPrintingProfile'
 import deleteMTGTag from './mutations/deleteMTGTag'
+import pinMTGCardVersion from './mutations/pinMTGCardVersion'
 import reimportMTGData from './mutations/reimportMTGData'
 import removeIgnoredCard from './mutations/removeIgnoredCard'
 import saveMTGDeckAsCopy from './mutations/saveMTGDeckAsCopy'
+import setMTGDeckPrintingProfile from './mutations/setMTGDeckPrintingProfile'
+import setMTGGlobalPrintingProfile from './mutations/setMTGGlobalPrintingProfile'
 import unassignTagFromCard from './mutations/unassignTagFromCard'
 import unassignTagFromDeck from './mutations/unassignTagFromDeck'
+import unpinMTGCardVersion from './mutations/unpinMTGCardVersion'
 import updateMTGDeck from './mutations/updateMTGDeck'
 import updateMTGFilterPreset from './mutations/updateMTGFilterPreset'
+import updateMTGPrintingProfile from './mutations/updateMTGPrintingProfile'
 import updateMTGTag from './mutations/updateMTGTag'
 import getMTGCards from './queries/getMTGCards'
 import getMTGCardsFiltered from './queries/getMTGCardsFiltered'
+import getMTGCatalogChanges from './queries/getMTGCatalogChanges'
 import getMTGDeck from './queries/getMTGDeck'
+import getMTGDeckTokens from './queries/getMTGDeckTokens'
 import getMTGDecks from './queries/getMTGDecks'
 import getMTGFilterPresets from './queries/getMTGFilterPresets'
 import getMTGImportStatus from './queries/getMTGImportStatus'
+import getMTGLegalityChanges from './queries/getMTGLegalityChanges'
+import getMTGPrintingProfiles from './queries/getMTGPrintingProfiles'
+import getMTGQuarantinedRecords from './queries/getMTGQuarantinedRecords'
 import getMTGTag from './queries/getMTGTag'
 import getMTGTagChains from './queries/getMTGTagChains'
 import getMTGTags from './queries/getMTGTags'
+import getMTGTokens from './queries/getMTGTokens'
 
 /**
  * MTG GraphQL API helpers
@@ -134,6 +172,30 @@ const getMTGDeckQuery = async (ID: string): Promise<MTG_Deck> =>
         })
     })
 
+/** Fetch the tokens and emblems created by the cards of a deck. */
+const getMTGDeckTokensQuery = async (deckID: string): Promise<MTG_DeckToken[]> =>
+    new Promise((resolve, reject) => {
+        fetchData<Query, QuerygetMTGDeckTokensArgs>(getMTGDeckTokens, { deckID }).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.getMTGDeckTokens)
+            } else {
+                reject('Failed to fetch MTG deck tokens')
+            }
+        })
+    })
+
+/** Search the token and emblem catalog by name, type line, or rules text. */
+const getMTGTokensQuery = async (args: QuerygetMTGTokensArgs): Promise<MTG_Token_Search> =>
+    new Promise((resolve, reject) => {
+        fetchData<Query, QuerygetMTGTokensArgs>(getMTGTokens, args).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.getMTGTokens)
+            } else {
+                reject('Failed to fetch MTG tokens')
+            }
+        })
+    })
+
 const getMTGFilterPresetsQuery = async (deckID: string): Promise<MTG_FilterPreset[]> =>
     new Promise((resolve, reject) => {
         fetchData<Query, QuerygetMTGFilterPresetsArgs>(getMTGFilterPresets, { deckID }).then((response) => {
@@ -145,6 +207,18 @@ const getMTGFilterPresetsQuery = async (deckID: string): Promise<MTG_FilterPrese
         })
     })
 
+/** Fetch all printing profiles. */
+const getMTGPrintingProfilesQuery = async (): Promise<MTG_PrintingProfile[]> =>
+    new Promise((resolve, reject) => {
+        fetchData<Query>(getMTGPrintingProfiles).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.getMTGPrintingProfiles)
+            } else {
+                reject('Failed to fetch MTG printing profiles')
+            }
+        })
+    })
+
 /** Fetch all tags. */
 const getMTGTagsQuery = async (): Promise<MTG_Tag[]> =>
     new Promise((resolve, reject) => {
@@ -193,6 +267,42 @@ const getMTGImportStatusQuery = async (): Promise<MTG_ImportStatus> =>
         })
     })
 
+/** Fetch the catalog changes recorded by imports after since (ISO timestamp). */
+const getMTGCatalogChangesQuery = async (since: string): Promise<MTG_CatalogChange[]> =>
+    new Promise((resolve, reject) => {
+        fetchData<Query, QuerygetMTGCatalogChangesArgs>(getMTGCatalogChanges, { since }).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.getMTGCatalogChanges)
+            } else {
+                reject('Failed to fetch catalog changes')
+            }
+        })
+    })
+
+/** Fetch the legality changes recorded by imports after since, optionally for one format. */
+const getMTGLegalityChangesQuery = async (args: QuerygetMTGLegalityChangesArgs): Promise<MTG_LegalityChange[]> =>
+    new Promise((resolve, reject) => {
+        fetchData<Query, QuerygetMTGLegalityChangesArgs>(getMTGLegalityChanges, args).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.getMTGLegalityChanges)
+            } else {
+                reject('Failed to fetch legality changes')
+            }
+        })
+    })
+
+/** Fetch the records an import run quarantined as malformed; defaults to the latest run. */
+const getMTGQuarantinedRecordsQuery = async (runID?: string): Promise<MTG_QuarantinedRecord[]> =>
+    new Promise((resolve, reject) => {
+        fetchData<Query, QuerygetMTGQuarantinedRecordsArgs>(getMTGQuarantinedRecords, { runID }).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.getMTGQuarantinedRecords)
+            } else {
+                reject('Failed to fetch quarantined records')
+            }
+        })
+    })
+
 // ----- MUTATIONS -----
 
 /** Create a new deck; Response.message contains new deck ID. */
@@ -279,6 +389,90 @@ const deleteMTGFilterPresetMutation = async (input: MTG_DeleteFilterPresetInput)
         })
     })
 
+/** Create a printing profile. */
+const createMTGPrintingProfileMutation = async (input: MTG_CreatePrintingProfileInput): Promise<MTG_PrintingProfile> =>
+    new Promise((resolve, reject) => {
+        fetchData<Mutation, MutationcreateMTGPrintingProfileArgs>(createMTGPrintingProfile, { input }).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.createMTGPrintingProfile)
+            } else {
+                reject('Failed to create MTG printing profile')
+            }
+        })
+    })
+
+/** Rename a printing profile or replace its rules. */
+const updateMTGPrintingProfileMutation = async (input: MTG_UpdatePrintingProfileInput): Promise<MTG_PrintingProfile> =>
+    new Promise((resolve, reject) => {
+        fetchData<Mutation, MutationupdateMTGPrintingProfileArgs>(updateMTGPrintingProfile, { input }).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.updateMTGPrintingProfile)
+            } else {
+                reject('Failed to update MTG printing profile')
+            }
+        })
+    })
+
+/** Delete a printing profile by ID. */
+const deleteMTGPrintingProfileMutation = async (input: MTG_DeletePrintingProfileInput): Promise<Response> =>
+    new Promise((resolve, reject) => {
+        fetchData<Mutation, MutationdeleteMTGPrintingProfileArgs>(deleteMTGPrintingProfile, { input }).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.deleteMTGPrintingProfile)
+            } else {
+                reject('Failed to delete MTG printing profile')
+            }
+        })
+    })
+
+/** Pin a card to one of its versions in a printing profile. */
+const pinMTGCardVersionMutation = async (input: MTG_PinCardVersionInput): Promise<MTG_PrintingProfile> =>
+    new Promise((resolve, reject) => {
+        fetchData<Mutation, MutationpinMTGCardVersionArgs>(pinMTGCardVersion, { input }).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.pinMTGCardVersion)
+            } else {
+                reject('Failed to pin MTG card version')
+            }
+        })
+    })
+
+/** Remove a card's pin from a printing profile. */
+const unpinMTGCardVersionMutation = async (input: MTG_UnpinCardVersionInput): Promise<MTG_PrintingProfile> =>
+    new Promise((resolve, reject) => {
+        fetchData<Mutation, MutationunpinMTGCardVersionArgs>(unpinMTGCardVersion, { input }).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.unpinMTGCardVersion)
+            } else {
+                reject('Failed to unpin MTG card version')
+            }
+        })
+    })
+
+/** Make a printing profile the global one; null clears it. */
+const setMTGGlobalPrintingProfileMutation = async (profileID: string | null): Promise<Response> =>
+    new Promise((resolve, reject) => {
+        fetchData<Mutation, MutationsetMTGGlobalPrintingProfileArgs>(setMTGGlobalPrintingProfile, { profileID }).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.setMTGGlobalPrintingProfile)
+            } else {
+                reject('Failed to set global MTG printing profile')
+            }
+        })
+    })
+
+/** Choose the printing profile used for a deck. */
+const setMTGDeckPrintingProfileMutation = async (input: MTG_SetDeckPrintingProfileInput): Promise<Response> =>
+    new Promise((resolve, reject) => {
+        fetchData<Mutation, MutationsetMTGDeckPrintingProfileArgs>(setMTGDeckPrintingProfile, { input }).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.setMTGDeckPrintingProfile)
+            } else {
+                reject('Failed to set deck MTG printing profile')
+            }
+        })
+    })
+
 /** Mark a card as ignored for a deck. */
 const addIgnoredCardMutation = async (input: AddIgnoredCardInput): Promise<Response> =>
     new Promise((resolve, reject) => {
@@ -399,17 +593,35 @@ const reimportMTGDataMutation = async (): Promise<MTG_ImportStatus> =>
         })
     })
 
+/** Cancel the running import of MTG cards and sets. */
+const cancelMTGImportMutation = async (): Promise<Response> =>
+    new Promise((resolve, reject) => {
+        fetchData<Mutation>(cancelMTGImport).then((response) => {
+            if (response && response.data && !response.errors) {
+                resolve(response.data.cancelMTGImport)
+            } else {
+                reject(response?.errors?.[0]?.message ?? 'Failed to cancel import')
+            }
+        })
+    })
+
 export const MTGFunctions = {
     queries: {
         getMTGCardsQuery,
         getMTGCardsFilteredQuery,
         getMTGDecksQuery,
         getMTGDeckQuery,
+        getMTGDeckTokensQuery,
         getMTGFilterPresetsQuery,
+        getMTGPrintingProfilesQuery,
         getMTGTagsQuery,
         getMTGTagQuery,
         getMTGTagChainsQuery,
+        getMTGTokensQuery,
         getMTGImportStatusQuery,
+        getMTGCatalogChangesQuery,
+        getMTGLegalityChangesQuery,
+        getMTGQuarantinedRecordsQuery,
     },
     mutations: {
         createMTGDeckMutation,
@@ -419,6 +631,13 @@ export const MTGFunctions = {
         createMTGFilterPresetMutation,
         updateMTGFilterPresetMutation,
         deleteMTGFilterPresetMutation,
+        createMTGPrintingProfileMutation,
+        updateMTGPrintingProfileMutation,
+        deleteMTGPrintingProfileMutation,
+        pinMTGCardVersionMutation,
+        unpinMTGCardVersionMutation,
+        setMTGGlobalPrintingProfileMutation,
+        setMTGDeckPrintingProfileMutation,
         addIgnoredCardMutation,
         removeIgnoredCardMutation,
         createMTGTagMutation,
@@ -429,5 +648,6 @@ export const MTGFunctions = {
         assignTagToDeckMutation,
         unassignTagFromDeckMutation,
         reimportMTGDataMutation,
+        cancelMTGImportMutation,
     },
 }
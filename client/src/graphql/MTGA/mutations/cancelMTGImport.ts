@@ -0,0 +1,10 @@
+import gql from 'graphql-tag'
+
+export default gql`
+    mutation cancelMTGImport {
+        cancelMTGImport {
+            status
+            message
+        }
+    }
+`
@@ -0,0 +1,11 @@
+import gql from 'graphql-tag'
+import { MTG_PrintingProfileFragments } from '../fragments'
+
+export default gql`
+    mutation createMTGPrintingProfile($input: MTG_CreatePrintingProfileInput!) {
+        createMTGPrintingProfile(input: $input) {
+            ...MTG_PrintingProfileFragment
+        }
+    }
+    ${MTG_PrintingProfileFragments}
+`
@@ -0,0 +1,10 @@
+import gql from 'graphql-tag'
+
+export default gql`
+    mutation deleteMTGPrintingProfile($input: MTG_DeletePrintingProfileInput!) {
+        deleteMTGPrintingProfile(input: $input) {
+            status
+            message
+        }
+    }
+`
@@ -0,0 +1,10 @@
+import gql from 'graphql-tag'
+
+export default gql`
+    mutation setMTGDeckPrintingProfile($input: MTG_SetDeckPrintingProfileInput!) {
+        setMTGDeckPrintingProfile(input: $input) {
+            status
+            message
+        }
+    }
+`
@@ -0,0 +1,10 @@
+import gql from 'graphql-tag'
+
+export default gql`
+    mutation setMTGGlobalPrintingProfile($profileID: ID) {
+        setMTGGlobalPrintingProfile(profileID: $profileID) {
+            status
+            message
+        }
+    }
+`
@@ -0,0 +1,25 @@
+import gql from 'graphql-tag'
+
+export default gql`
+    query getMTGCatalogChanges($since: String!) {
+        getMTGCatalogChanges(since: $since) {
+            ID
+            cardID
+            name
+            importedAt
+            added
+            fieldChanges {
+                field
+                before
+                after
+            }
+            newVersions {
+                ID
+                set
+                setName
+                collectorNumber
+                releasedAt
+            }
+        }
+    }
+`
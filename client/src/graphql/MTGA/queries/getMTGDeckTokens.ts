@@ -0,0 +1,14 @@
+import gql from 'graphql-tag'
+import { MTG_CardFragments } from '../fragments'
+
+export default gql`
+    query getMTGDeckTokens($deckID: ID!) {
+        getMTGDeckTokens(deckID: $deckID) {
+            token {
+                ...MTG_CardFragment
+            }
+            createdBy
+        }
+    }
+    ${MTG_CardFragments}
+`
@@ -32,6 +32,16 @@ export default gql`
                     }
                 }
             }
+            savedAt
+            legalityChanges {
+                ID
+                cardID
+                name
+                format
+                before
+                after
+                changedAt
+            }
         }
     }
     ${MTG_ImageFragments}
@@ -8,9 +8,26 @@ export default gql`
             inProgress
             phase
             progress
+            counters {
+                setsProcessed
+                bytesDownloaded
+                bytesTotal
+                cardsProcessed
+                batchesUpserted
+                groupsProcessed
+                groupsTotal
+                cardsInserted
+                cardsUpdated
+                cardsRemoved
+                originalsPruned
+                recordsQuarantined
+            }
+            etaSeconds
             startedAt
             completedAt
             error
+            failedPhase
+            nextScheduledRun
         }
     }
 `
@@ -0,0 +1,15 @@
+import gql from 'graphql-tag'
+
+export default gql`
+    query getMTGLegalityChanges($since: String!, $format: String) {
+        getMTGLegalityChanges(since: $since, format: $format) {
+            ID
+            cardID
+            name
+            format
+            before
+            after
+            changedAt
+        }
+    }
+`
@@ -0,0 +1,11 @@
+import gql from 'graphql-tag'
+import { MTG_PrintingProfileFragments } from '../fragments'
+
+export default gql`
+    query getMTGPrintingProfiles {
+        getMTGPrintingProfiles {
+            ...MTG_PrintingProfileFragment
+        }
+    }
+    ${MTG_PrintingProfileFragments}
+`
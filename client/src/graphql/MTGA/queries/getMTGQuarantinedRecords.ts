@@ -0,0 +1,15 @@
+import gql from 'graphql-tag'
+
+export default gql`
+    query getMTGQuarantinedRecords($runID: ID) {
+        getMTGQuarantinedRecords(runID: $runID) {
+            ID
+            runID
+            kind
+            recordID
+            raw
+            error
+            quarantinedAt
+        }
+    }
+`
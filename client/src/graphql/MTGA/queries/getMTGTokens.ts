@@ -0,0 +1,14 @@
+import gql from 'graphql-tag'
+import { MTG_CardFragments } from '../fragments'
+
+export default gql`
+    query getMTGTokens($search: String, $pagination: MTG_Filter_PaginationInput!) {
+        getMTGTokens(search: $search, pagination: $pagination) {
+            pagedTokens {
+                ...MTG_CardFragment
+            }
+            totalCount
+        }
+    }
+    ${MTG_CardFragments}
+`
@@ -75,14 +75,26 @@ export type MTG_Card = {
   colorIdentity: Array<MTG_Color>;
   colorIndicator?: Maybe<Array<Scalars['String']['output']>>;
   colors?: Maybe<Array<MTG_Color>>;
+  /** Defense of a battle. */
+  defense?: Maybe<Scalars['String']['output']>;
+  /** True if the card is on the Commander Game Changer list. */
+  gameChanger: Scalars['Boolean']['output'];
   keywords: Array<Scalars['String']['output']>;
   layout: MTG_Layout;
   loyalty?: Maybe<Scalars['String']['output']>;
   manaCost?: Maybe<Scalars['String']['output']>;
   name: Scalars['String']['output'];
   oracleText?: Maybe<Scalars['String']['output']>;
+  /** Popularity rank on Penny Dreadful, null when unranked. */
+  pennyRank?: Maybe<Scalars['Int']['output']>;
   power?: Maybe<Scalars['String']['output']>;
   producedMana?: Maybe<Array<MTG_Color>>;
+  /** Cards linked to this one through Scryfall's all_parts, such as meld partners and results, combo pieces and created tokens. */
+  relatedCards: Array<MTG_RelatedCard>;
+  /** True if the card is on the Reserved List. */
+  reserved: Scalars['Boolean']['output'];
+  /** Official rulings and Scryfall notes for this card, oldest first. */
+  rulings: Array<MTG_Ruling>;
   tagAssignments: Array<MTG_TagAssignment>;
   toughness?: Maybe<Scalars['String']['output']>;
   typeLine: Scalars['String']['output'];
@@ -96,6 +108,7 @@ export type MTG_CardFace = {
   artist?: Maybe<Scalars['String']['output']>;
   colorIndicator?: Maybe<Array<Scalars['String']['output']>>;
   colors?: Maybe<Array<MTG_Color>>;
+  defense?: Maybe<Scalars['String']['output']>;
   flavorText?: Maybe<Scalars['String']['output']>;
   imageUris?: Maybe<MTG_Image>;
   layout?: Maybe<MTG_Layout>;
@@ -104,8 +117,15 @@ export type MTG_CardFace = {
   name: Scalars['String']['output'];
   oracleText?: Maybe<Scalars['String']['output']>;
   power?: Maybe<Scalars['String']['output']>;
+  /** Localized name printed on this face of non-English versions. */
+  printedName?: Maybe<Scalars['String']['output']>;
+  /** Localized rules text printed on this face of non-English versions. */
+  printedText?: Maybe<Scalars['String']['output']>;
+  /** Localized type line printed on this face of non-English versions. */
+  printedTypeLine?: Maybe<Scalars['String']['output']>;
   toughness?: Maybe<Scalars['String']['output']>;
   typeLine?: Maybe<Scalars['String']['output']>;
+  watermark?: Maybe<Scalars['String']['output']>;
 };
 
 /** Minimal face data for dashboard UI. */
@@ -118,26 +138,66 @@ export type MTG_CardFace_Dashboard = {
 export type MTG_CardVersion = {
   __typename?: 'MTG_CardVersion';
   ID: Scalars['ID']['output'];
+  /** Catalog IDs of this printing on MTG Arena, Magic Online, TCGplayer and Cardmarket, when it has one. */
+  arenaID?: Maybe<Scalars['Int']['output']>;
   artist?: Maybe<Scalars['String']['output']>;
+  /** Border color: black, white, borderless, yellow, silver or gold. */
+  borderColor: Scalars['String']['output'];
   cardFaces?: Maybe<Array<MTG_CardFace>>;
+  cardmarketID?: Maybe<Scalars['Int']['output']>;
+  /** Position of this version in the built-in printing ordering, 0 being its default. */
+  defaultRank: Scalars['Int']['output'];
+  /** True if the printing was only released in a video game. */
+  digital: Scalars['Boolean']['output'];
+  /** Finishes the printing is available in: nonfoil, foil, etched or glossy. */
+  finishes: Array<Scalars['String']['output']>;
   flavorName?: Maybe<Scalars['String']['output']>;
   flavorText?: Maybe<Scalars['String']['output']>;
+  /** Frame edition, such as 1993, 1997, 2003, 2015 or future. */
+  frame: Scalars['String']['output'];
+  frameEffects?: Maybe<Array<Scalars['String']['output']>>;
   games: Array<MTG_Game>;
   imageUris?: Maybe<MTG_Image>;
   isAlchemy: Scalars['Boolean']['output'];
+  /** Default version under the printing profile in effect, or under the built-in ordering without one. */
   isDefault: Scalars['Boolean']['output'];
   lang: Scalars['String']['output'];
   legalities: Scalars['Map']['output'];
+  mtgoID?: Maybe<Scalars['Int']['output']>;
+  /** Daily price snapshots of the last days (30 by default), oldest first. */
+  priceHistory: Array<MTG_PricePoint>;
+  /** Latest market prices recorded from Scryfall, null when Scryfall listed none. */
+  prices?: Maybe<MTG_Prices>;
   printedName: Scalars['String']['output'];
+  /** Localized rules text printed on non-English versions. */
+  printedText?: Maybe<Scalars['String']['output']>;
+  /** Localized type line printed on non-English versions. */
+  printedTypeLine?: Maybe<Scalars['String']['output']>;
+  /** True if the printing is a promotional print. */
+  promo: Scalars['Boolean']['output'];
+  promoTypes?: Maybe<Array<Scalars['String']['output']>>;
   rarity: MTG_Rarity;
   releasedAt: Scalars['String']['output'];
   reprint: Scalars['Boolean']['output'];
+  /** Security stamp, if any: oval, triangle, acorn, circle, arena or heart. */
+  securityStamp?: Maybe<Scalars['String']['output']>;
   set: Scalars['String']['output'];
   setID: Scalars['String']['output'];
   setName: Scalars['String']['output'];
   setType: Scalars['String']['output'];
+  tcgplayerID?: Maybe<Scalars['Int']['output']>;
+  /** True if the printing has no rules text in its text box. */
+  textless: Scalars['Boolean']['output'];
   variation: Scalars['Boolean']['output'];
   variationOf?: Maybe<Scalars['String']['output']>;
+  watermark?: Maybe<Scalars['String']['output']>;
+};
+
+
+
+/** A specific printing/version of a card used by the app. */
+export type MTG_CardVersionpriceHistoryArgs = {
+  days?: InputMaybe<Scalars['Int']['input']>;
 };
 
 /** Minimal card version data for dashboard UI. */
@@ -158,6 +218,52 @@ export type MTG_Card_Dashboard = {
   versions: Array<MTG_CardVersion_Dashboard>;
 };
 
+/**
+ * What one import changed about a card. A card new to the catalog is only marked as
+ * added; its printings are not listed as new versions.
+ */
+export type MTG_CatalogChange = {
+  __typename?: 'MTG_CatalogChange';
+  ID: Scalars['ID']['output'];
+  /** Whether the card is new to the catalog, such as a freshly spoiled card. */
+  added: Scalars['Boolean']['output'];
+  cardID: Scalars['ID']['output'];
+  /** Oracle text, type line and mana cost changes. */
+  fieldChanges: Array<MTG_CatalogFieldChange>;
+  /** When the import recorded the change (ISO timestamp). Changes of one import share it. */
+  importedAt: Scalars['String']['output'];
+  name: Scalars['String']['output'];
+  /** Printings added to the card. */
+  newVersions: Array<MTG_CatalogNewVersion>;
+};
+
+/** Oracle field of a card tracked by the catalog change feed. */
+export enum MTG_CatalogField {
+  MANA_COST = 'MANA_COST',
+  ORACLE_TEXT = 'ORACLE_TEXT',
+  TYPE_LINE = 'TYPE_LINE'
+}
+
+/** A tracked Oracle field whose value changed in an import (errata). */
+export type MTG_CatalogFieldChange = {
+  __typename?: 'MTG_CatalogFieldChange';
+  /** Value after the import, null when the card has none. */
+  after?: Maybe<Scalars['String']['output']>;
+  /** Value before the import, null when the card had none. */
+  before?: Maybe<Scalars['String']['output']>;
+  field: MTG_CatalogField;
+};
+
+/** A printing that appeared on an existing card in an import. */
+export type MTG_CatalogNewVersion = {
+  __typename?: 'MTG_CatalogNewVersion';
+  ID: Scalars['ID']['output'];
+  collectorNumber: Scalars['String']['output'];
+  releasedAt: Scalars['String']['output'];
+  set: Scalars['String']['output'];
+  setName: Scalars['String']['output'];
+};
+
 /** Magic color identity abbreviations. */
 export enum MTG_Color {
   B = 'B',
@@ -183,6 +289,12 @@ export type MTG_CreateFilterPresetInput = {
   sortState: Array<MTG_Filter_SortInput>;
 };
 
+/** Input payload to create a printing profile. */
+export type MTG_CreatePrintingProfileInput = {
+  name: Scalars['String']['input'];
+  rules: Array<MTG_PrintingRuleInput>;
+};
+
 /** Input to create a new tag. */
 export type MTG_CreateTagInput = {
   meta?: InputMaybe<Scalars['Boolean']['input']>;
@@ -198,6 +310,8 @@ export type MTG_Deck = {
   cards: Array<MTG_DeckCard>;
   ignoredCards: Array<Scalars['String']['output']>;
   name: Scalars['String']['output'];
+  /** Printing profile deciding the default versions of this deck's cards; null uses the global profile. */
+  printingProfileID?: Maybe<Scalars['ID']['output']>;
   tags: Array<MTG_Tag>;
   type: DeckType;
   zones: Array<FlowZone>;
@@ -249,11 +363,29 @@ export type MTG_DeckDashboard = {
   ID: Scalars['ID']['output'];
   cardFrontImage?: Maybe<MTG_Deck_CardFrontImage>;
   cards: Array<MTG_DeckCard_Dashboard>;
+  /**
+   * Legality changes of the deck's cards recorded since the deck was last saved, limited to
+   * the deck's format when its type names one.
+   */
+  legalityChanges: Array<MTG_LegalityChange>;
   name: Scalars['String']['output'];
+  /**
+   * When the deck was last saved (ISO timestamp). Decks saved before this was tracked get the
+   * time the server first started tracking it.
+   */
+  savedAt?: Maybe<Scalars['String']['output']>;
   tags: Array<MTG_Tag>;
   type: DeckType;
 };
 
+/** A token or emblem a deck can create, with the deck's cards that create it. */
+export type MTG_DeckToken = {
+  __typename?: 'MTG_DeckToken';
+  /** Names of the cards in the deck that create the token, sorted. */
+  createdBy: Array<Scalars['String']['output']>;
+  token: MTG_Card;
+};
+
 /** Selected front image for a deck, referencing a card version. */
 export type MTG_Deck_CardFrontImage = {
   __typename?: 'MTG_Deck_CardFrontImage';
@@ -272,6 +404,11 @@ export type MTG_DeleteFilterPresetInput = {
   presetID: Scalars['ID']['input'];
 };
 
+/** Identifier wrapper for deleting a printing profile. */
+export type MTG_DeletePrintingProfileInput = {
+  profileID: Scalars['ID']['input'];
+};
+
 /** Input to delete a tag by ID. */
 export type MTG_DeleteTagInput = {
   tagID: Scalars['ID']['input'];
@@ -289,6 +426,12 @@ export type MTG_FilterPreset = {
   sortState: Array<MTG_Filter_SortState>;
 };
 
+/** Border color filter entry with ternary state. */
+export type MTG_Filter_BorderColorInput = {
+  borderColor: Scalars['String']['input'];
+  value: TernaryBoolean;
+};
+
 /** Card type filter entry with ternary state. */
 export type MTG_Filter_CardTypeInput = {
   cardType: Scalars['String']['input'];
@@ -318,10 +461,17 @@ export type MTG_Filter_ColorInput = {
 /** Aggregated entries used to render filter UI. */
 export type MTG_Filter_Entries = {
   __typename?: 'MTG_Filter_Entries';
+  /** Distinct printing border colors, frames, watermarks and security stamps present in the catalog. */
+  borderColors: Array<Scalars['String']['output']>;
   expansions: Array<MTG_Filter_Expansion>;
+  frames: Array<Scalars['String']['output']>;
+  /** Distinct printing languages present in the catalog. */
+  languages: Array<Scalars['String']['output']>;
   layouts: Array<MTG_Layout>;
   legality: MTG_Filter_Legality;
+  securityStamps: Array<Scalars['String']['output']>;
   types: Array<MTG_Filter_CardTypes>;
+  watermarks: Array<Scalars['String']['output']>;
 };
 
 /** Expansion metadata used by filters and sorting. */
@@ -335,12 +485,32 @@ export type MTG_Filter_Expansion = {
   setType: Scalars['String']['output'];
 };
 
+/** Catalog IDs of a printing. Cards match when one of their printings has every ID given. */
+export type MTG_Filter_ExternalIDInput = {
+  arenaID?: InputMaybe<Scalars['Int']['input']>;
+  cardmarketID?: InputMaybe<Scalars['Int']['input']>;
+  mtgoID?: InputMaybe<Scalars['Int']['input']>;
+  tcgplayerID?: InputMaybe<Scalars['Int']['input']>;
+};
+
+/** Frame filter entry with ternary state. */
+export type MTG_Filter_FrameInput = {
+  frame: Scalars['String']['input'];
+  value: TernaryBoolean;
+};
+
 /** Game platform filter entry with ternary state. */
 export type MTG_Filter_GameInput = {
   game: MTG_Game;
   value: TernaryBoolean;
 };
 
+/** Printing language filter entry with ternary state. Languages use Scryfall codes (en, es, ja...). */
+export type MTG_Filter_LanguageInput = {
+  lang: Scalars['String']['input'];
+  value: TernaryBoolean;
+};
+
 /** Layout filter entry with ternary state. */
 export type MTG_Filter_LayoutInput = {
   layout: MTG_Layout;
@@ -378,6 +548,22 @@ export type MTG_Filter_PaginationInput = {
   pageSize: Scalars['Int']['input'];
 };
 
+/**
+ * Price range in one currency. Cards match when their cheapest printing costs between min and
+ * max (both inclusive and optional); cards without a price in the currency never match.
+ */
+export type MTG_Filter_PriceInput = {
+  currency: MTG_PriceCurrency;
+  max?: InputMaybe<Scalars['Float']['input']>;
+  min?: InputMaybe<Scalars['Float']['input']>;
+};
+
+/** Integer range. Both bounds are inclusive and optional. */
+export type MTG_Filter_RangeInput = {
+  max?: InputMaybe<Scalars['Int']['input']>;
+  min?: InputMaybe<Scalars['Int']['input']>;
+};
+
 /** Rarity filter entry with ternary state. */
 export type MTG_Filter_RarityInput = {
   rarity: MTG_Rarity;
@@ -393,22 +579,46 @@ export type MTG_Filter_Search = {
 
 /** Combined filter input used to filter cards. */
 export type MTG_Filter_SearchInput = {
+  /** Filter by printing border color (TRUE = must have a printing with it, FALSE = ignore printings with it). */
+  borderColors?: InputMaybe<Array<MTG_Filter_BorderColorInput>>;
   cardTypes: Array<MTG_Filter_CardTypeInput>;
   /** Filter by exact chain sequences. */
   chains?: InputMaybe<Array<MTG_Filter_ChainInput>>;
   color: Array<MTG_Filter_ColorInput>;
   commander?: InputMaybe<Scalars['ID']['input']>;
   deckID?: InputMaybe<Scalars['ID']['input']>;
+  /** Defense range of battles; cards without a numeric defense never match. */
+  defense?: InputMaybe<MTG_Filter_RangeInput>;
+  /** Digital-only printings (TRUE = must have a digital printing, FALSE = must have a non-digital printing). */
+  digital?: InputMaybe<TernaryBoolean>;
+  /** Look a card up by the catalog IDs of one of its printings. */
+  externalIDs?: InputMaybe<MTG_Filter_ExternalIDInput>;
+  /** Filter by printing frame (TRUE = must have a printing with it, FALSE = ignore printings with it). */
+  frames?: InputMaybe<Array<MTG_Filter_FrameInput>>;
+  /** Game Changer list membership (TRUE = only Game Changers, FALSE = no Game Changers). */
+  gameChanger?: InputMaybe<TernaryBoolean>;
   games: Array<MTG_Filter_GameInput>;
   hideIgnored: Scalars['Boolean']['input'];
   hideUnreleased: Scalars['Boolean']['input'];
   isSelectingCommander: Scalars['Boolean']['input'];
+  /** Filter by printing language (TRUE = must have a printing in it, FALSE = ignore printings in it). */
+  languages?: InputMaybe<Array<MTG_Filter_LanguageInput>>;
   layouts: Array<MTG_Filter_LayoutInput>;
   legalities: Array<MTG_Filter_LegalityInput>;
   manaCosts: Array<MTG_Filter_ManaCostInput>;
   multiColor: TernaryBoolean;
+  /** Penny Dreadful rank range; unranked cards never match. */
+  pennyRank?: InputMaybe<MTG_Filter_RangeInput>;
+  /** Filter by the price of the cheapest printing (any finish). */
+  price?: InputMaybe<MTG_Filter_PriceInput>;
+  /** Promotional printings (TRUE = must have a promo printing, FALSE = must have a non-promo printing). */
+  promo?: InputMaybe<TernaryBoolean>;
   rarity: Array<MTG_Filter_RarityInput>;
+  /** Reserved List membership (TRUE = only reserved cards, FALSE = no reserved cards). */
+  reserved?: InputMaybe<TernaryBoolean>;
   searchString?: InputMaybe<Scalars['String']['input']>;
+  /** Filter by printing security stamp (TRUE = must have a printing with it, FALSE = ignore printings with it). */
+  securityStamps?: InputMaybe<Array<MTG_Filter_SecurityStampInput>>;
   sets: Array<MTG_Filter_SetInput>;
   subtypes: Array<MTG_Filter_SubtypeInput>;
   /**
@@ -417,6 +627,16 @@ export type MTG_Filter_SearchInput = {
    * Matches if tag appears ANYWHERE in chain (terminal or chain member).
    */
   tags: Array<MTG_Filter_TagInput>;
+  /** Textless printings (TRUE = must have a textless printing, FALSE = must have a printing with text). */
+  textless?: InputMaybe<TernaryBoolean>;
+  /** Filter by printing watermark (TRUE = must have a printing with it, FALSE = ignore printings with it). */
+  watermarks?: InputMaybe<Array<MTG_Filter_WatermarkInput>>;
+};
+
+/** Security stamp filter entry with ternary state. */
+export type MTG_Filter_SecurityStampInput = {
+  securityStamp: Scalars['String']['input'];
+  value: TernaryBoolean;
 };
 
 /** Set filter entry with ternary state. */
@@ -430,6 +650,8 @@ export enum MTG_Filter_SortBy {
   CMC = 'CMC',
   COLOR = 'COLOR',
   NAME = 'NAME',
+  /** Cheapest printing, in the currency of the price filter (USD without one). Cards without a price sort last. */
+  PRICE = 'PRICE',
   RARITY = 'RARITY',
   RELEASED_AT = 'RELEASED_AT',
   SET = 'SET',
@@ -472,6 +694,12 @@ export type MTG_Filter_TagInput = {
   value: TernaryBoolean;
 };
 
+/** Watermark filter entry with ternary state. */
+export type MTG_Filter_WatermarkInput = {
+  value: TernaryBoolean;
+  watermark: Scalars['String']['input'];
+};
+
 /** Game platforms where a print is available. */
 export enum MTG_Game {
   arena = 'arena',
@@ -500,9 +728,100 @@ export enum MTG_ImportPhase {
   PROCESSING_CARDS = 'PROCESSING_CARDS',
   REBUILDING_INDEX = 'REBUILDING_INDEX',
   COMPLETE = 'COMPLETE',
-  FAILED = 'FAILED'
+  FAILED = 'FAILED',
+  CANCELLED = 'CANCELLED'
 }
 
+/** Timing of one phase of an import run. */
+export type MTG_ImportPhaseTiming = {
+  __typename?: 'MTG_ImportPhaseTiming';
+  durationMs: Scalars['Int']['output'];
+  phase: MTG_ImportPhase;
+  /** When the phase started (ISO timestamp). */
+  startedAt: Scalars['String']['output'];
+};
+
+/** A finished import run, as recorded in the import history. */
+export type MTG_ImportRun = {
+  __typename?: 'MTG_ImportRun';
+  ID: Scalars['ID']['output'];
+  /** Changes to mtg_cards. */
+  cards: MTG_ImportRunCounts;
+  durationMs: Scalars['Int']['output'];
+  /** Error message if the run failed. */
+  error?: Maybe<Scalars['String']['output']>;
+  /** Phase that was running when the run failed. */
+  failedPhase?: Maybe<MTG_ImportPhase>;
+  /** When the run finished (ISO timestamp). */
+  finishedAt: Scalars['String']['output'];
+  /** Original printings archived or deleted because the bulk file no longer had them. */
+  originalsPruned: Scalars['Int']['output'];
+  /** Phases in the order they ran. */
+  phases: Array<MTG_ImportPhaseTiming>;
+  /** Malformed records set aside in quarantine instead of stopping the run. */
+  recordsQuarantined: Scalars['Int']['output'];
+  /** Changes to mtg_sets. Sets are never removed. */
+  sets: MTG_ImportRunCounts;
+  /** When the run started (ISO timestamp). */
+  startedAt: Scalars['String']['output'];
+  /** COMPLETE, FAILED or CANCELLED. */
+  status: MTG_ImportPhase;
+  trigger: MTG_ImportTrigger;
+};
+
+/** How an import run changed a collection. */
+export type MTG_ImportRunCounts = {
+  __typename?: 'MTG_ImportRunCounts';
+  added: Scalars['Int']['output'];
+  removed: Scalars['Int']['output'];
+  unchanged: Scalars['Int']['output'];
+  updated: Scalars['Int']['output'];
+};
+
+/**
+ * Local files to import from instead of downloading from Scryfall. Files are
+ * names relative to the server's import.importDir; absolute paths and names
+ * leaving that directory are rejected. Omitted fields fall back to the server's
+ * import settings.
+ */
+export type MTG_ImportSourceInput = {
+  /** Name of a Scryfall bulk cards file, such as default_cards.json. */
+  cardsFile?: InputMaybe<Scalars['String']['input']>;
+  /** Name of a Scryfall rulings bulk file (rulings.json). */
+  rulingsFile?: InputMaybe<Scalars['String']['input']>;
+  /** Name of a Scryfall sets file (a JSON array of sets or a saved /sets response). */
+  setsFile?: InputMaybe<Scalars['String']['input']>;
+};
+
+/** Running totals of an import. Counters of steps that have not started yet are 0. */
+export type MTG_ImportCounters = {
+  __typename?: 'MTG_ImportCounters';
+  /** Number of sets stored. */
+  setsProcessed: Scalars['Int']['output'];
+  /** Bytes of the bulk card file downloaded so far (Float because the file can exceed 2 GB). */
+  bytesDownloaded: Scalars['Float']['output'];
+  /** Total size of the bulk card file in bytes, 0 when unknown. */
+  bytesTotal: Scalars['Float']['output'];
+  /** Number of original card records upserted. */
+  cardsProcessed: Scalars['Int']['output'];
+  /** Number of original card batches upserted. */
+  batchesUpserted: Scalars['Int']['output'];
+  /** Number of card groups built so far. */
+  groupsProcessed: Scalars['Int']['output'];
+  /** Total number of card groups to build. */
+  groupsTotal: Scalars['Int']['output'];
+  /** Number of cards inserted into the catalog. */
+  cardsInserted: Scalars['Int']['output'];
+  /** Number of catalog cards whose content changed. */
+  cardsUpdated: Scalars['Int']['output'];
+  /** Number of cards removed from the catalog. */
+  cardsRemoved: Scalars['Int']['output'];
+  /** Number of original printings pruned because the import no longer saw them. */
+  originalsPruned: Scalars['Int']['output'];
+  /** Number of malformed records set aside in quarantine. */
+  recordsQuarantined: Scalars['Int']['output'];
+};
+
 /** Status response for import operations. */
 export type MTG_ImportStatus = {
   __typename?: 'MTG_ImportStatus';
@@ -516,13 +835,29 @@ export type MTG_ImportStatus = {
   phase: MTG_ImportPhase;
   /** Progress percentage (0-100). */
   progress: Scalars['Int']['output'];
+  /** Running totals of the current or last import. */
+  counters: MTG_ImportCounters;
+  /** Estimated seconds until the current step finishes, null when unknown. */
+  etaSeconds?: Maybe<Scalars['Int']['output']>;
   /** When the import started (ISO timestamp). */
   startedAt?: Maybe<Scalars['String']['output']>;
   /** When the import completed (ISO timestamp). */
   completedAt?: Maybe<Scalars['String']['output']>;
-  /** Error message if the import failed. */
+  /** Error message if the import failed, prefixed with the phase that failed. */
   error?: Maybe<Scalars['String']['output']>;
-};
+  /** Phase that was running when the import failed, null unless phase is FAILED. */
+  failedPhase?: Maybe<MTG_ImportPhase>;
+  /** When the scheduler starts the next import (ISO timestamp), null when scheduled imports are off. */
+  nextScheduledRun?: Maybe<Scalars['String']['output']>;
+};
+
+/** What started an import run. */
+export enum MTG_ImportTrigger {
+  /** Started with the reimportMTGData mutation. */
+  MANUAL = 'MANUAL',
+  /** Started by the import scheduler. */
+  SCHEDULED = 'SCHEDULED'
+}
 
 /** Card layouts as defined by Scryfall. */
 export enum MTG_Layout {
@@ -552,6 +887,160 @@ export enum MTG_Layout {
   vanguard = 'vanguard'
 }
 
+/**
+ * A change of a card's legality in one format, recorded by an import (a ban, unban,
+ * restriction or rotation).
+ */
+export type MTG_LegalityChange = {
+  __typename?: 'MTG_LegalityChange';
+  ID: Scalars['ID']['output'];
+  /** legal, not_legal, restricted or banned after the import; null when the format is no longer listed. */
+  after?: Maybe<Scalars['String']['output']>;
+  /** legal, not_legal, restricted or banned before the import; null when the format was not listed. */
+  before?: Maybe<Scalars['String']['output']>;
+  cardID: Scalars['ID']['output'];
+  /** When the import recorded the change (ISO timestamp). */
+  changedAt: Scalars['String']['output'];
+  /** Scryfall format key, such as standard or commander. */
+  format: Scalars['String']['output'];
+  name: Scalars['String']['output'];
+};
+
+/** Pin a card to one of its versions within a printing profile. */
+export type MTG_PinCardVersionInput = {
+  cardID: Scalars['ID']['input'];
+  profileID: Scalars['ID']['input'];
+  versionID: Scalars['ID']['input'];
+};
+
+/** Currencies Scryfall publishes prices in. */
+export enum MTG_PriceCurrency {
+  EUR = 'EUR',
+  TIX = 'TIX',
+  USD = 'USD'
+}
+
+/** Prices of a card version on one day. */
+export type MTG_PricePoint = {
+  __typename?: 'MTG_PricePoint';
+  /** Snapshot date (YYYY-MM-DD, UTC). */
+  date: Scalars['String']['output'];
+  prices: MTG_Prices;
+};
+
+/**
+ * Market prices of a card version as published by Scryfall. Prices are null when
+ * Scryfall has none for that finish or currency.
+ */
+export type MTG_Prices = {
+  __typename?: 'MTG_Prices';
+  eur?: Maybe<Scalars['Float']['output']>;
+  eurEtched?: Maybe<Scalars['Float']['output']>;
+  eurFoil?: Maybe<Scalars['Float']['output']>;
+  tix?: Maybe<Scalars['Float']['output']>;
+  usd?: Maybe<Scalars['Float']['output']>;
+  usdEtched?: Maybe<Scalars['Float']['output']>;
+  usdFoil?: Maybe<Scalars['Float']['output']>;
+};
+
+/** A card whose default printing is fixed to one version. */
+export type MTG_PrintingPin = {
+  __typename?: 'MTG_PrintingPin';
+  cardID: Scalars['ID']['output'];
+  versionID: Scalars['ID']['output'];
+};
+
+/**
+ * A named set of printing preferences. The rules are applied in order: the first rule
+ * that tells two printings apart decides which one is preferred, and the import's
+ * built-in ordering breaks the remaining ties. Pins override the rules for single cards.
+ */
+export type MTG_PrintingProfile = {
+  __typename?: 'MTG_PrintingProfile';
+  ID: Scalars['ID']['output'];
+  /** True for the profile applied wherever no deck profile is chosen. */
+  isGlobal: Scalars['Boolean']['output'];
+  name: Scalars['String']['output'];
+  pins: Array<MTG_PrintingPin>;
+  rules: Array<MTG_PrintingRule>;
+};
+
+/**
+ * One printing preference. Printings matching the rule are preferred, or avoided when
+ * avoid is set; value is ignored for OLDEST and NEWEST.
+ */
+export type MTG_PrintingRule = {
+  __typename?: 'MTG_PrintingRule';
+  avoid: Scalars['Boolean']['output'];
+  kind: MTG_PrintingRuleKind;
+  value?: Maybe<Scalars['String']['output']>;
+};
+
+/** A printing preference rule. value is required for every kind except OLDEST and NEWEST. */
+export type MTG_PrintingRuleInput = {
+  avoid?: InputMaybe<Scalars['Boolean']['input']>;
+  kind: MTG_PrintingRuleKind;
+  value?: InputMaybe<Scalars['String']['input']>;
+};
+
+/**
+ * What a printing preference rule compares. OLDEST and NEWEST order by release date;
+ * every other kind matches printings against the rule's value.
+ */
+export enum MTG_PrintingRuleKind {
+  /** Printings illustrated by this artist. */
+  ARTIST = 'ARTIST',
+  /** Printings with this border color (black, white, borderless, yellow, silver, gold). */
+  BORDER_COLOR = 'BORDER_COLOR',
+  /** Printings available in this finish (nonfoil, foil, etched, glossy). */
+  FINISH = 'FINISH',
+  /** Printings with this frame edition (1993, 1997, 2003, 2015, future). */
+  FRAME = 'FRAME',
+  /** Printings with this frame effect (showcase, extendedart, etched...). */
+  FRAME_EFFECT = 'FRAME_EFFECT',
+  /** Printings available in this game (paper, mtgo, arena). */
+  GAME = 'GAME',
+  /** Printings in this language (Scryfall code). */
+  LANGUAGE = 'LANGUAGE',
+  /** The most recently released printing. */
+  NEWEST = 'NEWEST',
+  /** The earliest released printing. */
+  OLDEST = 'OLDEST',
+  /** Printings with this promo type (prerelease, boosterfun...). */
+  PROMO_TYPE = 'PROMO_TYPE',
+  /** Printings from the set with this code. */
+  SET = 'SET'
+}
+
+/** Import step that rejected a quarantined record. */
+export enum MTG_QuarantineKind {
+  /** A Scryfall card of the bulk file that failed to decode or validate. */
+  CARD = 'CARD',
+  /** A stored original card that could not be grouped into the catalog. */
+  CARD_GROUP = 'CARD_GROUP',
+  /** An MTGJSON set, with its cards, that failed to decode or validate. */
+  MTGJSON_SET = 'MTGJSON_SET',
+  /** A Scryfall set that failed to decode or validate. */
+  SET = 'SET'
+}
+
+/** A malformed upstream record an import set aside instead of aborting. */
+export type MTG_QuarantinedRecord = {
+  __typename?: 'MTG_QuarantinedRecord';
+  ID: Scalars['ID']['output'];
+  /** Why the record was rejected. */
+  error: Scalars['String']['output'];
+  kind: MTG_QuarantineKind;
+  /** When the record was quarantined (ISO timestamp). */
+  quarantinedAt: Scalars['String']['output'];
+  /** The record as received, as JSON. */
+  raw: Scalars['String']['output'];
+  /** Scryfall ID or set code of the record, when it could be read. */
+  recordID?: Maybe<Scalars['String']['output']>;
+  /** Import run that last rejected the record. */
+  runID: Scalars['ID']['output'];
+};
+
 /** Rarity tiers for a printing. */
 export enum MTG_Rarity {
   common = 'common',
@@ -560,6 +1049,37 @@ export enum MTG_Rarity {
   uncommon = 'uncommon'
 }
 
+/** A card related to another one, with the role it plays in that relationship. */
+export type MTG_RelatedCard = {
+  __typename?: 'MTG_RelatedCard';
+  card: MTG_Card;
+  component: MTG_RelatedCardComponent;
+};
+
+/** Role a related card plays in a relationship listed in Scryfall's all_parts. */
+export enum MTG_RelatedCardComponent {
+  combo_piece = 'combo_piece',
+  meld_part = 'meld_part',
+  meld_result = 'meld_result',
+  token = 'token'
+}
+
+/** A ruling or note on a card, as published in Scryfall's rulings bulk file. */
+export type MTG_Ruling = {
+  __typename?: 'MTG_Ruling';
+  comment: Scalars['String']['output'];
+  /** Publication date (YYYY-MM-DD). */
+  publishedAt: Scalars['String']['output'];
+  /** Who published it: wotc or scryfall. */
+  source: Scalars['String']['output'];
+};
+
+/** Choose the printing profile of a deck; a null profileID falls back to the global profile. */
+export type MTG_SetDeckPrintingProfileInput = {
+  deckID: Scalars['ID']['input'];
+  profileID?: InputMaybe<Scalars['ID']['input']>;
+};
+
 /** A tag that can be assigned to cards and decks. */
 export type MTG_Tag = {
   __typename?: 'MTG_Tag';
@@ -576,6 +1096,13 @@ export type MTG_TagAssignment = {
   tag: MTG_Tag;
 };
 
+/** Page of tokens and emblems matching a token search, with the total count for pagination. */
+export type MTG_Token_Search = {
+  __typename?: 'MTG_Token_Search';
+  pagedTokens: Array<MTG_Card>;
+  totalCount: Scalars['Int']['output'];
+};
+
 /** Unassign a tag from a card. */
 export type MTG_UnassignTagFromCardInput = {
   cardID: Scalars['ID']['input'];
@@ -589,6 +1116,12 @@ export type MTG_UnassignTagFromDeckInput = {
   tagID: Scalars['ID']['input'];
 };
 
+/** Remove a card's pin from a printing profile. */
+export type MTG_UnpinCardVersionInput = {
+  cardID: Scalars['ID']['input'];
+  profileID: Scalars['ID']['input'];
+};
+
 /** Input to update deck fields, cards, zones and front image. */
 export type MTG_UpdateDeckInput = {
   autosave: Scalars['Boolean']['input'];
@@ -609,6 +1142,13 @@ export type MTG_UpdateFilterPresetInput = {
   sortState?: InputMaybe<Array<MTG_Filter_SortInput>>;
 };
 
+/** Fields allowed when updating a printing profile. rules replaces the whole list. */
+export type MTG_UpdatePrintingProfileInput = {
+  name?: InputMaybe<Scalars['String']['input']>;
+  profileID: Scalars['ID']['input'];
+  rules?: InputMaybe<Array<MTG_PrintingRuleInput>>;
+};
+
 /** Input to update an existing tag. */
 export type MTG_UpdateTagInput = {
   meta?: InputMaybe<Scalars['Boolean']['input']>;
@@ -625,32 +1165,54 @@ export type Mutation = {
   assignTagToCard: Response;
   /** Assign a tag to a deck. */
   assignTagToDeck: Response;
+  /**
+   * Cancel the running import. The import stops at its next request or batch and
+   * its status becomes CANCELLED; status is false when no import is running.
+   */
+  cancelMTGImport: Response;
   /** Create a new deck and return its ID in Response.message. */
   createMTGDeck: Response;
   /** Save a new filter preset for a deck. */
   createMTGFilterPreset: MTG_FilterPreset;
+  /** Create a printing profile. */
+  createMTGPrintingProfile: MTG_PrintingProfile;
   /** Create a new tag. */
   createMTGTag: MTG_Tag;
   /** Delete a deck by ID. */
   deleteMTGDeck: Response;
   /** Delete a filter preset. */
   deleteMTGFilterPreset: Response;
+  /** Delete a printing profile; decks using it fall back to the global profile. */
+  deleteMTGPrintingProfile: Response;
   /** Delete a tag by ID. */
   deleteMTGTag: Response;
-  /** Trigger a manual re-import of MTG cards and sets from Scryfall. */
+  /** Pin a card to one of its versions in a printing profile, replacing an earlier pin. */
+  pinMTGCardVersion: MTG_PrintingProfile;
+  /**
+   * Trigger a manual re-import of MTG cards and sets from Scryfall, or from local
+   * bulk files when a source is given. Returns immediately; import runs in background.
+   */
   reimportMTGData: MTG_ImportStatus;
   /** Remove ignored mark from a deck/card pair. */
   removeIgnoredCard: Response;
   /** Create a new deck by copying another deck's data. */
   saveMTGDeckAsCopy: Response;
+  /** Choose the printing profile used for a deck. */
+  setMTGDeckPrintingProfile: Response;
+  /** Make a printing profile the global one, or clear the global profile with a null profileID. */
+  setMTGGlobalPrintingProfile: Response;
   /** Unassign a tag from a card. */
   unassignTagFromCard: Response;
   /** Unassign a tag from a deck. */
   unassignTagFromDeck: Response;
+  /** Remove a card's pin from a printing profile. */
+  unpinMTGCardVersion: MTG_PrintingProfile;
   /** Replace deck fields and card edges. */
   updateMTGDeck: Response;
   /** Update an existing filter preset. */
   updateMTGFilterPreset: MTG_FilterPreset;
+  /** Rename a printing profile or replace its rules. */
+  updateMTGPrintingProfile: MTG_PrintingProfile;
   /** Update an existing tag. */
   updateMTGTag?: Maybe<MTG_Tag>;
 };
@@ -686,6 +1248,12 @@ export type MutationcreateMTGFilterPresetArgs = {
 };
 
 
+/** Root-level write operations. */
+export type MutationcreateMTGPrintingProfileArgs = {
+  input: MTG_CreatePrintingProfileInput;
+};
+
+
 /** Root-level write operations. */
 export type MutationcreateMTGTagArgs = {
   input: MTG_CreateTagInput;
@@ -704,12 +1272,30 @@ export type MutationdeleteMTGFilterPresetArgs = {
 };
 
 
+/** Root-level write operations. */
+export type MutationdeleteMTGPrintingProfileArgs = {
+  input: MTG_DeletePrintingProfileInput;
+};
+
+
 /** Root-level write operations. */
 export type MutationdeleteMTGTagArgs = {
   input: MTG_DeleteTagInput;
 };
 
 
+/** Root-level write operations. */
+export type MutationpinMTGCardVersionArgs = {
+  input: MTG_PinCardVersionInput;
+};
+
+
+/** Root-level write operations. */
+export type MutationreimportMTGDataArgs = {
+  source?: InputMaybe<MTG_ImportSourceInput>;
+};
+
+
 /** Root-level write operations. */
 export type MutationremoveIgnoredCardArgs = {
   input: RemoveIgnoredCardInput;
@@ -722,6 +1308,18 @@ export type MutationsaveMTGDeckAsCopyArgs = {
 };
 
 
+/** Root-level write operations. */
+export type MutationsetMTGDeckPrintingProfileArgs = {
+  input: MTG_SetDeckPrintingProfileInput;
+};
+
+
+/** Root-level write operations. */
+export type MutationsetMTGGlobalPrintingProfileArgs = {
+  profileID?: InputMaybe<Scalars['ID']['input']>;
+};
+
+
 /** Root-level write operations. */
 export type MutationunassignTagFromCardArgs = {
   input: MTG_UnassignTagFromCardInput;
@@ -734,6 +1332,12 @@ export type MutationunassignTagFromDeckArgs = {
 };
 
 
+/** Root-level write operations. */
+export type MutationunpinMTGCardVersionArgs = {
+  input: MTG_UnpinCardVersionInput;
+};
+
+
 /** Root-level write operations. */
 export type MutationupdateMTGDeckArgs = {
   input: MTG_UpdateDeckInput;
@@ -746,6 +1350,12 @@ export type MutationupdateMTGFilterPresetArgs = {
 };
 
 
+/** Root-level write operations. */
+export type MutationupdateMTGPrintingProfileArgs = {
+  input: MTG_UpdatePrintingProfileInput;
+};
+
+
 /** Root-level write operations. */
 export type MutationupdateMTGTagArgs = {
   input: MTG_UpdateTagInput;
@@ -785,20 +1395,43 @@ export type Query = {
   getMTGCardsFiltered: MTG_Filter_Search;
   /** Return a single deck by ID with cards and metadata. */
   getMTGDeck: MTG_Deck;
+  /** List every token and emblem the cards of a deck can create, sorted by name. */
+  getMTGDeckTokens: Array<MTG_DeckToken>;
   /** List all decks for dashboard view. */
   getMTGDecks: Array<MTG_DeckDashboard>;
   /** List saved filter presets for a deck. */
   getMTGFilterPresets: Array<MTG_FilterPreset>;
   /** Return available filter options (types, layouts, expansions, legalities). */
   getMTGFilters: MTG_Filter_Entries;
+  /** List all printing profiles, sorted by name. */
+  getMTGPrintingProfiles: Array<MTG_PrintingProfile>;
   /** Return a single tag by ID. */
   getMTGTag?: Maybe<MTG_Tag>;
   /** Return all unique tag chains that exist on cards. */
   getMTGTagChains: Array<MTG_TagAssignment>;
   /** List all tags. */
   getMTGTags: Array<MTG_Tag>;
+  /** Search the token and emblem catalog by name, type line or rules text (all tokens without a search). */
+  getMTGTokens: MTG_Token_Search;
   /** Get current status of the card/set import process. */
   getMTGImportStatus: MTG_ImportStatus;
+  /** List the most recent import runs, newest first (default 20). */
+  getMTGImportHistory: Array<MTG_ImportRun>;
+  /**
+   * List the cards added, changed (oracle text, type line, mana cost) or given new printings
+   * by imports after since (ISO timestamp), newest import first.
+   */
+  getMTGCatalogChanges: Array<MTG_CatalogChange>;
+  /**
+   * List the legality changes recorded by imports after since (ISO timestamp), newest first,
+   * optionally limited to one format.
+   */
+  getMTGLegalityChanges: Array<MTG_LegalityChange>;
+  /**
+   * List the records an import run quarantined as malformed, by kind and record ID.
+   * Defaults to the latest finished run.
+   */
+  getMTGQuarantinedRecords: Array<MTG_QuarantinedRecord>;
 };
 
 
@@ -810,23 +1443,61 @@ export type QuerygetMTGCardsFilteredArgs = {
 };
 
 
+/** Root-level read operations. */
+export type QuerygetMTGCatalogChangesArgs = {
+  since: Scalars['String']['input'];
+};
+
+
 /** Root-level read operations. */
 export type QuerygetMTGDeckArgs = {
   deckID: Scalars['ID']['input'];
 };
 
 
+/** Root-level read operations. */
+export type QuerygetMTGDeckTokensArgs = {
+  deckID: Scalars['ID']['input'];
+};
+
+
 /** Root-level read operations. */
 export type QuerygetMTGFilterPresetsArgs = {
   deckID: Scalars['ID']['input'];
 };
 
 
+/** Root-level read operations. */
+export type QuerygetMTGImportHistoryArgs = {
+  limit?: InputMaybe<Scalars['Int']['input']>;
+};
+
+
+/** Root-level read operations. */
+export type QuerygetMTGLegalityChangesArgs = {
+  format?: InputMaybe<Scalars['String']['input']>;
+  since: Scalars['String']['input'];
+};
+
+
+/** Root-level read operations. */
+export type QuerygetMTGQuarantinedRecordsArgs = {
+  runID?: InputMaybe<Scalars['ID']['input']>;
+};
+
+
 /** Root-level read operations. */
 export type QuerygetMTGTagArgs = {
   tagID: Scalars['ID']['input'];
 };
 
+
+/** Root-level read operations. */
+export type QuerygetMTGTokensArgs = {
+  pagination: MTG_Filter_PaginationInput;
+  search?: InputMaybe<Scalars['String']['input']>;
+};
+
 /** Remove an ignored mark for a deck/card pair. */
 export type RemoveIgnoredCardInput = {
   cardID: Scalars['ID']['input'];
@@ -663,6 +663,15 @@ export const filterCards = <T extends MTG_Card>(
                             s.sortDirection === MTG_Filter_SortDirection.ASC
                                 ? Math.min(...c.versions.map((v) => new Date(v.releasedAt).getTime()))
                                 : Math.max(...c.versions.map((v) => new Date(v.releasedAt).getTime()))
+                    case MTG_Filter_SortBy.PRICE:
+                        return (c: MTG_Card) =>
+                            Math.min(
+                                ...c.versions.flatMap((v) =>
+                                    [v.prices?.usd, v.prices?.usdFoil, v.prices?.usdEtched].filter(
+                                        (p): p is number => p != null,
+                                    ),
+                                ),
+                            )
                 }
             })
             .flat(),
@@ -15,6 +15,11 @@ export default defineConfig({
                 changeOrigin: true,
                 secure: false,
             },
+            '/image': {
+                target: 'http://localhost:8080',
+                changeOrigin: true,
+                secure: false,
+            },
         },
         hmr: {
             overlay: true, // Shows errors as overlay
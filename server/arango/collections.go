@@ -8,17 +8,27 @@ type ArangoDocument string
 const (
 	// Application collections
 	APPLICATION_CONFIG_COLLECTION ArangoDocument = "application_config"
+	MTG_IMPORT_RUNS_COLLECTION    ArangoDocument = "mtg_import_runs"
 	// MTG Original data from Scryfall
-	MTG_ORIGINAL_SETS_COLLECTION  ArangoDocument = "mtg_original_sets"
-	MTG_ORIGINAL_CARDS_COLLECTION ArangoDocument = "mtg_original_cards"
+	MTG_ORIGINAL_SETS_COLLECTION          ArangoDocument = "mtg_original_sets"
+	MTG_ORIGINAL_CARDS_COLLECTION         ArangoDocument = "mtg_original_cards"
+	MTG_ORIGINAL_CARDS_ARCHIVE_COLLECTION ArangoDocument = "mtg_original_cards_archive"
 	// MTG collections
-	MTG_SETS_COLLECTION  ArangoDocument = "mtg_sets"
-	MTG_CARDS_COLLECTION ArangoDocument = "mtg_cards"
+	MTG_SETS_COLLECTION             ArangoDocument = "mtg_sets"
+	MTG_CARDS_COLLECTION            ArangoDocument = "mtg_cards"
+	MTG_CARD_KEY_ALIASES_COLLECTION ArangoDocument = "mtg_card_key_aliases"
+	MTG_CARD_PRICES_COLLECTION      ArangoDocument = "mtg_card_prices"
+	MTG_CARD_RULINGS_COLLECTION     ArangoDocument = "mtg_card_rulings"
+	MTG_TOKENS_COLLECTION           ArangoDocument = "mtg_tokens"
+	MTG_CATALOG_CHANGES_COLLECTION  ArangoDocument = "mtg_catalog_changes"
+	MTG_LEGALITY_CHANGES_COLLECTION ArangoDocument = "mtg_legality_changes"
+	MTG_QUARANTINE_COLLECTION       ArangoDocument = "mtg_quarantine"
 	// MTG user collections
-	MTG_DECKS_COLLECTION          ArangoDocument = "mtg_decks"
-	MTG_FILTER_PRESETS_COLLECTION ArangoDocument = "mtg_filter_presets"
-	MTG_TAGS_COLLECTION           ArangoDocument = "mtg_tags"
-	MTG_CARD_PACKAGES_COLLECTION  ArangoDocument = "mtg_card_packages"
+	MTG_DECKS_COLLECTION             ArangoDocument = "mtg_decks"
+	MTG_FILTER_PRESETS_COLLECTION    ArangoDocument = "mtg_filter_presets"
+	MTG_TAGS_COLLECTION              ArangoDocument = "mtg_tags"
+	MTG_CARD_PACKAGES_COLLECTION     ArangoDocument = "mtg_card_packages"
+	MTG_PRINTING_PROFILES_COLLECTION ArangoDocument = "mtg_printing_profiles"
 )
 
 func (d ArangoDocument) String() string {
@@ -29,17 +39,27 @@ func (d ArangoDocument) String() string {
 var DOCUMENT_COLLECTIONS_ARRAY = []ArangoDocument{
 	// Application collections
 	APPLICATION_CONFIG_COLLECTION,
+	MTG_IMPORT_RUNS_COLLECTION,
 	// MTG Original data from Scryfall
 	MTG_ORIGINAL_SETS_COLLECTION,
 	MTG_ORIGINAL_CARDS_COLLECTION,
+	MTG_ORIGINAL_CARDS_ARCHIVE_COLLECTION,
 	// MTG collections
 	MTG_SETS_COLLECTION,
 	MTG_CARDS_COLLECTION,
+	MTG_CARD_KEY_ALIASES_COLLECTION,
+	MTG_CARD_PRICES_COLLECTION,
+	MTG_CARD_RULINGS_COLLECTION,
+	MTG_TOKENS_COLLECTION,
+	MTG_CATALOG_CHANGES_COLLECTION,
+	MTG_LEGALITY_CHANGES_COLLECTION,
+	MTG_QUARANTINE_COLLECTION,
 	// MTG user collections
 	MTG_DECKS_COLLECTION,
 	MTG_FILTER_PRESETS_COLLECTION,
 	MTG_TAGS_COLLECTION,
 	MTG_CARD_PACKAGES_COLLECTION,
+	MTG_PRINTING_PROFILES_COLLECTION,
 }
 
 // ArangoEdge represents the name of an edge collection.
@@ -60,6 +80,8 @@ const (
 	MTG_TAG_TO_CARD_PACKAGE_EDGE ArangoEdge = "mtg_tag_to_card_package"
 	// Card Package
 	MTG_CARD_IN_CARD_PACKAGE_EDGE ArangoEdge = "mtg_card_in_card_package"
+	// Card relations
+	MTG_CARD_RELATED_CARD_EDGE ArangoEdge = "mtg_card_related_card"
 )
 
 func (e ArangoEdge) String() string {
@@ -82,13 +104,23 @@ var EDGE_COLLECTIONS_ARRAY = []ArangoEdge{
 	MTG_TAG_TO_CARD_PACKAGE_EDGE,
 	// Card Package
 	MTG_CARD_IN_CARD_PACKAGE_EDGE,
+	// Card relations
+	MTG_CARD_RELATED_CARD_EDGE,
 }
 
 type ArangoIndexEnum string
 
 const (
-	MTG_CARDS_BUILDUP_INDEX   ArangoIndexEnum = "mtg_cards_buildup"
-	MTG_TAGS_NAME_UNIQUE_INDEX ArangoIndexEnum = "mtg_tags_name_unique"
+	MTG_ORIGINAL_CARDS_RUN_INDEX    ArangoIndexEnum = "mtg_original_cards_run"
+	MTG_CARDS_BUILDUP_INDEX         ArangoIndexEnum = "mtg_cards_buildup"
+	MTG_CARDS_VERSION_ID_INDEX      ArangoIndexEnum = "mtg_cards_version_id"
+	MTG_CARD_PRICES_VERSION_INDEX   ArangoIndexEnum = "mtg_card_prices_version"
+	MTG_CARD_PRICES_DATE_INDEX      ArangoIndexEnum = "mtg_card_prices_date"
+	MTG_TAGS_NAME_UNIQUE_INDEX      ArangoIndexEnum = "mtg_tags_name_unique"
+	MTG_CATALOG_CHANGES_DATE_INDEX  ArangoIndexEnum = "mtg_catalog_changes_date"
+	MTG_LEGALITY_CHANGES_CARD_INDEX ArangoIndexEnum = "mtg_legality_changes_card"
+	MTG_LEGALITY_CHANGES_DATE_INDEX ArangoIndexEnum = "mtg_legality_changes_date"
+	MTG_QUARANTINE_RUN_INDEX        ArangoIndexEnum = "mtg_quarantine_run"
 )
 
 func (i ArangoIndexEnum) String() string {
@@ -107,6 +139,16 @@ type ArangoIndexMap map[ArangoIndexEnum]ArangoIndexStruct
 
 // INDEX_ARRAY holds the set of indexes that must be ensured at startup.
 var INDEX_ARRAY ArangoIndexMap = map[ArangoIndexEnum]ArangoIndexStruct{
+	MTG_ORIGINAL_CARDS_RUN_INDEX: {
+		CollectionName: MTG_ORIGINAL_CARDS_COLLECTION.String(),
+		IsEdge:         false,
+		Fields:         []string{"importRunID"},
+		Options: &arangoDriver.EnsurePersistentIndexOptions{
+			Unique: false,
+			Sparse: false,
+			Name:   MTG_ORIGINAL_CARDS_RUN_INDEX.String(),
+		},
+	},
 	MTG_CARDS_BUILDUP_INDEX: {
 		CollectionName: MTG_CARDS_COLLECTION.String(),
 		IsEdge:         false,
@@ -117,6 +159,76 @@ var INDEX_ARRAY ArangoIndexMap = map[ArangoIndexEnum]ArangoIndexStruct{
 			Name:   MTG_CARDS_BUILDUP_INDEX.String(),
 		},
 	},
+	MTG_CARDS_VERSION_ID_INDEX: {
+		CollectionName: MTG_CARDS_COLLECTION.String(),
+		IsEdge:         false,
+		Fields:         []string{"versions[*].ID"},
+		Options: &arangoDriver.EnsurePersistentIndexOptions{
+			Unique: false,
+			Sparse: false,
+			Name:   MTG_CARDS_VERSION_ID_INDEX.String(),
+		},
+	},
+	MTG_CARD_PRICES_VERSION_INDEX: {
+		CollectionName: MTG_CARD_PRICES_COLLECTION.String(),
+		IsEdge:         false,
+		Fields:         []string{"versionID", "date"},
+		Options: &arangoDriver.EnsurePersistentIndexOptions{
+			Unique: false,
+			Sparse: false,
+			Name:   MTG_CARD_PRICES_VERSION_INDEX.String(),
+		},
+	},
+	MTG_CARD_PRICES_DATE_INDEX: {
+		CollectionName: MTG_CARD_PRICES_COLLECTION.String(),
+		IsEdge:         false,
+		Fields:         []string{"date"},
+		Options: &arangoDriver.EnsurePersistentIndexOptions{
+			Unique: false,
+			Sparse: false,
+			Name:   MTG_CARD_PRICES_DATE_INDEX.String(),
+		},
+	},
+	MTG_CATALOG_CHANGES_DATE_INDEX: {
+		CollectionName: MTG_CATALOG_CHANGES_COLLECTION.String(),
+		IsEdge:         false,
+		Fields:         []string{"importedAt"},
+		Options: &arangoDriver.EnsurePersistentIndexOptions{
+			Unique: false,
+			Sparse: false,
+			Name:   MTG_CATALOG_CHANGES_DATE_INDEX.String(),
+		},
+	},
+	MTG_LEGALITY_CHANGES_CARD_INDEX: {
+		CollectionName: MTG_LEGALITY_CHANGES_COLLECTION.String(),
+		IsEdge:         false,
+		Fields:         []string{"cardID", "changedAt"},
+		Options: &arangoDriver.EnsurePersistentIndexOptions{
+			Unique: false,
+			Sparse: false,
+			Name:   MTG_LEGALITY_CHANGES_CARD_INDEX.String(),
+		},
+	},
+	MTG_LEGALITY_CHANGES_DATE_INDEX: {
+		CollectionName: MTG_LEGALITY_CHANGES_COLLECTION.String(),
+		IsEdge:         false,
+		Fields:         []string{"changedAt"},
+		Options: &arangoDriver.EnsurePersistentIndexOptions{
+			Unique: false,
+			Sparse: false,
+			Name:   MTG_LEGALITY_CHANGES_DATE_INDEX.String(),
+		},
+	},
+	MTG_QUARANTINE_RUN_INDEX: {
+		CollectionName: MTG_QUARANTINE_COLLECTION.String(),
+		IsEdge:         false,
+		Fields:         []string{"runID"},
+		Options: &arangoDriver.EnsurePersistentIndexOptions{
+			Unique: false,
+			Sparse: false,
+			Name:   MTG_QUARANTINE_RUN_INDEX.String(),
+		},
+	},
 	MTG_TAGS_NAME_UNIQUE_INDEX: {
 		CollectionName: MTG_TAGS_COLLECTION.String(),
 		IsEdge:         false,
@@ -1,20 +1,22 @@
 package daemons
 
 import (
-	"bytes"
+	"bufio"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"magic-helper/arango"
 	"magic-helper/graph/model"
 	"magic-helper/graph/model/scryfall"
 	scryfallModel "magic-helper/graph/model/scryfall/model"
 	"magic-helper/graph/mtg"
+	"magic-helper/settings"
 	"magic-helper/util/mtgCardSearch"
 	"math"
 	"os"
-	"path/filepath"
 	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -30,6 +32,15 @@ type ScryfallResponse struct {
 	NextPage string            `json:"next_page"`
 }
 
+// ScryfallBulkData describes one entry of the Scryfall bulk-data listing.
+type ScryfallBulkData struct {
+	Type        string `json:"type"`
+	DownloadURI string `json:"download_uri"`
+	UpdatedAt   string `json:"updated_at"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+}
+
 // ProgressReader wraps an io.Reader, tracks read bytes, and logs progress.
 type ProgressReader struct {
 	Reader      io.Reader
@@ -37,12 +48,16 @@ type ProgressReader struct {
 	ReadSoFar   int64
 	LogInterval int64 // Log every N bytes
 	NextLogAt   int64
+	OnProgress  func(read, total int64) // Optional, called after every read
 }
 
 // Read implements the io.Reader interface for ProgressReader.
 func (pr *ProgressReader) Read(p []byte) (n int, err error) {
 	n, err = pr.Reader.Read(p)
 	pr.ReadSoFar += int64(n)
+	if pr.OnProgress != nil && n > 0 {
+		pr.OnProgress(pr.ReadSoFar, pr.TotalSize)
+	}
 
 	// Check if it's time to log progress
 	if pr.ReadSoFar >= pr.NextLogAt {
@@ -91,47 +106,42 @@ func NewProgressReader(reader io.Reader, totalSize int64) *ProgressReader {
 	}
 }
 
-// PeriodicFetchMTGCards runs a 24h loop to fetch, process, and index MTG cards from Scryfall.
-func PeriodicFetchMTGCards() {
-	log.Info().Msg("Starting periodic fetch cards daemon")
-	ctx := context.Background() // Create context once for the loop iteration
-	for {
-		runMTGCardsCycle(ctx)
-
-		time.Sleep(24 * time.Hour)
+// fetchMTGCards imports cards from the local bulk file configured in source, or
+// checks whether a new download is needed and, if so, locates the card bulk
+// dataset ("default_cards", or "all_cards" when localized printings are kept)
+// and processes it. It returns false without an error when Scryfall has not
+// republished the dataset since the last import. For downloads it returns the
+// fetch state to save once the catalog is stored. Progress is reported to m when
+// it is not nil.
+func fetchMTGCards(ctx context.Context, source ImportSource, m *ImportManager) ([]model.MTGApplicationConfig, bool, error) {
+	if source.CardsFile != "" {
+		log.Info().Msgf("Importing cards from local file %s", source.CardsFile)
+		if err := processCardFile(ctx, source.CardsFile, false, m); err != nil {
+			log.Error().Err(err).Msgf("Error processing card data from %s", source.CardsFile)
+			return nil, false, err
+		}
+		return nil, true, nil
 	}
-}
 
-// fetchMTGCards checks whether a new download is needed and, if so, locates
-// the "default_cards" bulk dataset and processes it.
-func fetchMTGCards(ctx context.Context) bool {
 	log.Info().Msg("Fetching cards from Scryfall bulk data endpoint")
-	bulkDataUrl := "https://api.scryfall.com/bulk-data"
+	bulkDataUrl := scryfallAPIURL("/bulk-data")
 
-	// Check if we should fetch cards
-	shouldFetch := true
-	var err error
-	shouldFetch, err = shouldDownloadStart("MTG_cards")
+	// Load what was imported last time
+	state, err := readFetchState("MTG_cards")
 	if err != nil {
-		log.Error().Err(err).Msgf("Error checking if we should fetch cards")
-		return false
-	}
-	if !shouldFetch {
-		return false
+		log.Error().Err(err).Msgf("Error reading cards fetch state")
+		return nil, false, err
 	}
 
-	// Fetch the bulk data list
-	respList, err := fetchURLWithContext(ctx, bulkDataUrl) // Renamed resp to respList for clarity
+	// Fetch the bulk data list, unless it is unchanged since the last fetch
+	bodyList, etag, changed, err := fetchBodyIfChanged(ctx, bulkDataUrl, state.ETag)
 	if err != nil {
 		log.Error().Err(err).Msg("Error fetching bulk data list")
-		return false
+		return nil, false, err
 	}
-	defer respList.Body.Close() // Close list response
-
-	bodyList, err := io.ReadAll(respList.Body) // Read list body
-	if err != nil {
-		log.Error().Err(err).Msgf("Error reading bulk data list response body")
-		return false
+	if !changed {
+		log.Info().Msg("Bulk data list unchanged since last fetch, skipping")
+		return nil, false, nil
 	}
 
 	// Unmarshal the bulk data list JSON
@@ -139,94 +149,122 @@ func fetchMTGCards(ctx context.Context) bool {
 	err = json.Unmarshal(bodyList, &bulkDataResponse)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error unmarshalling bulk data list response body")
-		return false
+		return nil, false, fmt.Errorf("decoding bulk data list: %w", err)
 	}
 
-	rawCollections := bulkDataResponse.Data
-	for _, collection := range rawCollections {
-		var collectionMap map[string]any
-		err := json.Unmarshal(collection, &collectionMap)
+	bulkType := cardsBulkType()
+	found := false
+	for _, collection := range bulkDataResponse.Data {
+		var bulkData ScryfallBulkData
+		err := json.Unmarshal(collection, &bulkData)
 		if err != nil {
 			log.Error().Err(err).Msgf("Error unmarshalling collection item")
-			// Maybe continue to next item instead of returning false? For now, return.
-			return false
+			return nil, false, fmt.Errorf("decoding bulk data item: %w", err)
 		}
 
-		if collectionType, ok := collectionMap["type"].(string); ok && collectionType == "default_cards" {
-			downloadURI, uriOk := collectionMap["download_uri"].(string)
-			if !uriOk {
-				log.Error().Msgf("Could not get download_uri string from collection item")
-				continue // Skip this item if URI is not valid
-			}
-
-			log.Info().Msgf("Found 'default_cards' data. Fetching from: %s", downloadURI)
+		if bulkData.Type != bulkType {
+			continue
+		}
+		if bulkData.DownloadURI == "" {
+			log.Error().Msgf("Could not get download_uri string from collection item")
+			continue // Skip this item if URI is not valid
+		}
 
-			// Fetch and process card data
-			err = fetchAndProcessCardData(ctx, downloadURI)
-			if err != nil {
-				log.Error().Err(err).Msgf("Error processing card data from %s", downloadURI)
-				return false
+		// The list also changes when other datasets are republished; skip
+		// processing when our dataset is the one we imported last time.
+		if bulkData.Type == state.BulkType && bulkData.UpdatedAt == state.BulkUpdatedAt && bulkData.Size == state.BulkSize {
+			log.Info().Str("updatedAt", bulkData.UpdatedAt).Msgf("'%s' data unchanged since last fetch, skipping", bulkType)
+			state.ETag = etag
+			if err := saveFetchState(state); err != nil {
+				log.Error().Err(err).Msgf("Error saving cards fetch state")
 			}
+			return nil, false, nil
+		}
 
-			// Only process the first "default_cards" entry found? If yes, we can break here.
-			// If multiple "default_cards" entries exist (unlikely but possible), this would only process the first.
-			// Let's assume only one is relevant and break after successfully processing it.
-			break
+		log.Info().Msgf("Found '%s' data. Fetching from: %s", bulkType, bulkData.DownloadURI)
+
+		// Fetch and process card data
+		if err := fetchAndProcessCardData(ctx, bulkData, m); err != nil {
+			log.Error().Err(err).Msgf("Error processing card data from %s", bulkData.DownloadURI)
+			return nil, false, err
 		}
-	} // End of loop through bulk data items
 
-	// Update the last time we fetched cards (only if successful?)
-	// Move this inside the loop before the 'break' if we only process one file?
-	// Or keep it here to update even if no 'default_cards' was found (perhaps debatable)
-	err = updateLastTimeFetched("MTG_cards")
-	if err != nil {
-		log.Error().Err(err).Msgf("Error updating last time fetched")
-		// Don't return false here, as the main fetch might have succeeded
+		// Only one entry per bulk type is published.
+		state.BulkType = bulkData.Type
+		state.BulkUpdatedAt = bulkData.UpdatedAt
+		state.BulkSize = bulkData.Size
+		found = true
+		break
 	}
+	if !found {
+		return nil, false, fmt.Errorf("bulk data list has no downloadable %q entry", bulkType)
+	}
+
+	// The fetch is only recorded once the catalog is stored, so a failed run is
+	// retried.
+	state.ETag = etag
 
 	log.Info().Msgf("Card fetching process completed.")
 
-	return true
+	return []model.MTGApplicationConfig{state}, true, nil
 }
 
-// fetchAndProcessCardData fetches a bulk JSON file of cards and incrementally
-// decodes and upserts them into Arango in batches while logging progress.
-// It returns the number of card documents processed.
-func fetchAndProcessCardData(ctx context.Context, downloadURI string) error {
-	respData, err := fetchURLWithContext(ctx, downloadURI)
-	if err != nil {
-		log.Error().Err(err).Msgf("Error fetching card data file from %s", downloadURI)
+// fetchAndProcessCardData streams a bulk JSON file of cards to disk (resuming a
+// previous partial download when possible) and then decodes and upserts the
+// cards into Arango in batches straight from the file. Transient download
+// failures are retried, each attempt resuming where the previous one stopped.
+func fetchAndProcessCardData(ctx context.Context, bulkData ScryfallBulkData, m *ImportManager) error {
+	var filePath string
+	err := retryWithBackoff(ctx, "download "+bulkData.DownloadURI, func() error {
+		var err error
+		filePath, err = downloadBulkFile(ctx, bulkData.DownloadURI, bulkData.Type+".json", bulkData.Size, m.reportDownload)
 		return err
-	}
-	defer respData.Body.Close()
-
-	bodyBytes, err := io.ReadAll(respData.Body)
+	})
 	if err != nil {
-		log.Error().Err(err).Msg("Error reading response body")
+		log.Error().Err(err).Msgf("Error downloading card data file from %s", bulkData.DownloadURI)
 		return err
 	}
 
-	if err := downloadFile(bytes.NewReader(bodyBytes), "cards.json"); err != nil {
-		log.Error().Err(err).Msgf("Error downloading card data file from %s", downloadURI)
-		return err
-	}
+	log.Info().Msgf("Processing cards from %v", filePath)
+	return processCardFile(ctx, filePath, true, m)
+}
 
-	contentLengthStr := respData.Header.Get("Content-Length")
-	totalSize, _ := strconv.ParseInt(contentLengthStr, 10, 64)
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	reader io.Reader
+	read   int64
+}
+
+// Read implements the io.Reader interface for countingReader.
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.reader.Read(p)
+	cr.read += int64(n)
+	return n, err
+}
 
-	if totalSize > 0 {
-		log.Info().Msgf("Starting download of card data file (%d bytes)...", totalSize)
-	} else {
-		log.Info().Msg("Starting download of card data file (size unknown)...")
+// processCardFile decodes a Scryfall card array from disk one object at a time
+// and upserts the cards in batches, so memory use does not depend on file size.
+// With prune set, the originals the file no longer contains are pruned once it
+// is stored. Only a full upstream bulk download may prune: a local file can be a
+// small snapshot, and pruning to it would shrink the catalog to that snapshot.
+func processCardFile(ctx context.Context, filePath string, prune bool, m *ImportManager) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error opening card data file %s", filePath)
+		return err
 	}
+	defer file.Close()
 
-	progressReader := NewProgressReader(bytes.NewReader(bodyBytes), totalSize)
-	log.Info().Msgf("Processing cards from %v", downloadURI)
-	decoder := json.NewDecoder(progressReader)
+	var fileSize int64
+	if info, err := file.Stat(); err == nil {
+		fileSize = info.Size()
+	}
+	counter := &countingReader{reader: file}
+	decoder := json.NewDecoder(bufio.NewReaderSize(counter, 1024*1024))
 
 	if _, err = decoder.Token(); err != nil {
-		if err == io.EOF && progressReader.ReadSoFar == 0 {
-			log.Error().Msg("Download stream empty or failed immediately.")
+		if err == io.EOF {
+			log.Error().Msg("Card data file is empty.")
 		} else {
 			log.Error().Err(err).Msg("Error decoding card data: expecting start of array `[`")
 		}
@@ -234,8 +272,16 @@ func fetchAndProcessCardData(ctx context.Context, downloadURI string) error {
 	}
 
 	batchSize := 1000
+	processed := 0
+	stored := 0
+	runID := m.runID()
 
 	for decoder.More() {
+		// Stop between batches; the original cards upserted so far are kept.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		var batchRaw []json.RawMessage
 		for len(batchRaw) < batchSize && decoder.More() {
 			var cardRaw json.RawMessage
@@ -253,69 +299,88 @@ func fetchAndProcessCardData(ctx context.Context, downloadURI string) error {
 			continue
 		}
 
-		parsedArr, parseErr := parseCardsFromRaw(batchRaw)
-		if parseErr != nil {
-			log.Error().Err(parseErr).Msgf("Error parsing raw cards batch")
-			return parseErr
-		}
-
-		aq := arango.NewQuery( /* aql */ `
-			FOR c IN @cards
-				UPSERT { _key: c.id }
-					INSERT MERGE({ _key: c.id }, c)
-					UPDATE MERGE(OLD, c)
-				IN mtg_original_cards
-		`)
-		aq.AddBindVar("cards", parsedArr)
-
-		_, err = arango.DB.Query(ctx, aq.Query, aq.BindVars)
+		batchStored, err := storeOriginalBatch(ctx, batchRaw, runID, m)
 		if err != nil {
-			log.Error().Err(err).Msg("failed to upsert cards with diff")
 			return err
 		}
 
+		processed += len(batchRaw)
+		stored += batchStored
+		m.reportCardBatch(len(batchRaw), counter.read, fileSize)
+		log.Debug().Int("cards", processed).Msg("Upserted card batch")
 	}
 
-	log.Info().Msgf("Finished processing cards from %s.", downloadURI)
+	log.Info().Msgf("Finished processing %d cards from %s.", processed, filePath)
 
-	return nil
+	if !prune {
+		log.Info().Msg("Cards read from a local file, skipping original card pruning")
+		return nil
+	}
+	return pruneUnseenOriginals(ctx, stored, runID, m)
 }
 
-// collectCards rebuilds the curated mtg_cards collection from mtg_original_cards
-// by grouping variants and picking a default version per group.
-func collectCards(ctx context.Context) {
-	log.Info().Msg("Collecting cards")
+// storeOriginalBatch validates a batch of raw Scryfall cards, quarantines the
+// malformed ones and upserts the rest into mtg_original_cards, tagged with
+// runID. It returns the number of cards stored.
+func storeOriginalBatch(ctx context.Context, batchRaw []json.RawMessage, runID string, m *ImportManager) (int, error) {
+	parsedArr, quarantined := parseCardsFromRaw(batchRaw, runID)
+	if err := storeQuarantined(ctx, quarantined, m); err != nil {
+		return 0, err
+	}
+	if err := keepQuarantinedOriginals(ctx, quarantined, runID); err != nil {
+		return 0, err
+	}
 
-	// Clear the collection
-	aq := arango.NewQuery( /* aql */ `
-		FOR c IN mtg_cards
-			REMOVE c IN mtg_cards
-	`)
+	if len(parsedArr) > 0 {
+		if err := upsertOriginalCards(ctx, parsedArr, runID); err != nil {
+			return 0, err
+		}
+	}
+	return len(parsedArr), nil
+}
 
-	_, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+// pruneUnseenOriginals prunes the originals the run with runID did not store,
+// once a full upstream file has been stored.
+func pruneUnseenOriginals(ctx context.Context, stored int, runID string, m *ImportManager) error {
+	// An empty file, or one where every card was quarantined, would prune every
+	// original, which is never what we want.
+	if stored == 0 {
+		log.Warn().Msg("No cards stored, skipping original card pruning")
+		return nil
+	}
+	pruned, err := pruneOriginalCards(ctx, runID)
 	if err != nil {
-		log.Error().Err(err).Msg("Error clearing collection")
+		return err
 	}
+	m.reportPruned(pruned)
+	log.Info().Int("pruned", pruned).Str("mode", settings.Current.Import.PruneOriginals).Msg("Pruned original cards no longer in the bulk file")
+	return nil
+}
+
+// collectCards builds the curated card catalog from mtg_original_cards by
+// grouping variants and picking a default version per group, along with the
+// tokens and the all_parts relations of every card. Progress is reported to m
+// when it is not nil.
+func collectCards(ctx context.Context, m *ImportManager) (CardCatalog, error) {
+	log.Info().Msg("Collecting cards")
 
 	// Collect the cards
-	aq = arango.NewQuery( /* aql */ `
+	aq := arango.NewQuery( /* aql */ `
 	FOR c IN mtg_original_cards
-		FILTER c.lang == "en" // TODO: Remove this later
+		FILTER c.lang IN @languages
 		FILTER c.set_type NOT IN ["promo", "funny", "memorabilia", "vanguard", "token"]
 		FILTER "paper" IN c.games OR "mtgo" IN c.games OR "arena" IN c.games
   		FILTER "legal" IN FLATTEN(VALUES(c.legalities)) // TODO: Remove this later
 		FILTER c.oversized == false
 
-		// First, handle the "A-" prefix
-		LET nameWithoutPrefix = STARTS_WITH(c.name, "A-") ? SUBSTRING(c.name, 2) : c.name
-		
-		// Next, handle the "Name // Name" pattern
-		LET parts = SPLIT(nameWithoutPrefix, " // ")
-		LET groupName = LENGTH(parts) == 2 ? parts[0] : nameWithoutPrefix
+		// Group printings by oracle identity. Reversible cards only carry the
+		// oracle ID on their faces; anything without one falls back to its name.
+		LET oracleID = NOT_NULL(c.oracle_id, c.card_faces[0].oracle_id)
+		LET groupKey = oracleID != null ? oracleID : c.name
 		
-		SORT DATE_TIMESTAMP(c.released_at) ASC // First show non-reprints, then reprints
-		// Collect based on the derived groupName, unset metadata
-		COLLECT key = groupName INTO cardDocs = UNSET(c, "_key", "_id", "_rev") 
+		SORT DATE_TIMESTAMP(c.released_at) ASC, c.id ASC // First show non-reprints, then reprints
+		// Collect based on the oracle identity, unset metadata
+		COLLECT key = groupKey INTO cardDocs = UNSET(c, "_key", "_id", "_rev") 
 		// LIMIT 1000
 		RETURN {
 			key: key,
@@ -323,14 +388,17 @@ func collectCards(ctx context.Context) {
 			cardDocuments: cardDocs 
 		}
 	`)
+	aq.AddBindVar("languages", importLanguages())
 
 	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
 	if err != nil {
 		log.Error().Err(err).Msg("Error querying database")
-		return
+		return CardCatalog{}, err
 	}
 
 	allGroups := make(map[string][]scryfall.Card) // Initialize the map
+	runID := m.runID()
+	var quarantined []model.MTGQuarantinedRecordDB
 	defer cursor.Close()
 	for cursor.HasMore() {
 		// Use a struct that matches the RETURN structure of the AQL query
@@ -342,7 +410,7 @@ func collectCards(ctx context.Context) {
 		_, err := cursor.ReadDocument(ctx, &groupResult)
 		if err != nil {
 			log.Error().Err(err).Msg("Error reading document")
-			// Consider continuing or returning based on error handling strategy
+			quarantined = append(quarantined, quarantineRecord(model.MtgQuarantineKindCardGroup, groupResult.Key, nil, err, runID))
 			continue
 		}
 
@@ -355,20 +423,28 @@ func collectCards(ctx context.Context) {
 			cardMap, ok := cardInterface.(map[string]interface{})
 			if !ok {
 				log.Warn().Msgf("Item %d in group '%s' is not a map[string]interface{}, skipping", i, groupKey)
+				raw, _ := json.Marshal(cardInterface)
+				quarantined = append(quarantined, quarantineRecord(model.MtgQuarantineKindCardGroup, "", raw, fmt.Errorf("item %d in group %q is not an object", i, groupKey), runID))
 				continue
 			}
+			cardID, _ := cardMap["id"].(string)
 
 			// 2. Convert map[string]interface{} to scryfall.Card
 			jsonData, jsonErr := json.Marshal(cardMap)
 			if jsonErr != nil {
 				log.Error().Err(jsonErr).Msgf("Error marshaling card map %d in group '%s' back to JSON", i, groupKey)
+				quarantined = append(quarantined, quarantineRecord(model.MtgQuarantineKindCardGroup, cardID, nil, jsonErr, runID))
 				continue
 			}
 
 			var card scryfall.Card
 			unmarshalErr := json.Unmarshal(jsonData, &card)
+			if unmarshalErr == nil {
+				unmarshalErr = validateCard(card)
+			}
 			if unmarshalErr != nil {
 				log.Error().Err(unmarshalErr).Msgf("Error unmarshaling JSON back to scryfall.Card for item %d in group '%s'", i, groupKey)
+				quarantined = append(quarantined, quarantineRecord(model.MtgQuarantineKindCardGroup, cardID, jsonData, unmarshalErr, runID))
 				continue
 			}
 
@@ -383,6 +459,9 @@ func collectCards(ctx context.Context) {
 	} // End HasMore loop
 
 	log.Info().Msgf("Collected %d valid groups", len(allGroups)) // Changed log message slightly
+	if err := storeQuarantined(ctx, quarantined, m); err != nil {
+		return CardCatalog{}, err
+	}
 
 	// Create the necessary directories if they don't exist
 	cardsDir := "cards"
@@ -393,11 +472,12 @@ func collectCards(ctx context.Context) {
 		if err != nil {
 			log.Error().Err(err).Msgf("Error creating directory %s", cardsDir)
 			// Decide if we should continue without saving JSON or return
-			return // Return for now if we can't create the dir
+			return CardCatalog{}, err // Return for now if we can't create the dir
 		}
 	}
 
 	allCardsToSave := make([]scryfall.MTG_CardDB, 0)
+	relatedParts := make(map[string][]scryfall.RelatedCard) // card key -> all_parts of its printings
 
 	log.Info().Msgf("Processing %d groups", len(allGroups))
 
@@ -408,6 +488,10 @@ func collectCards(ctx context.Context) {
 	logThreshold := progressInterval
 
 	for key, groupData := range allGroups { // Renamed variables for clarity
+		if err := ctx.Err(); err != nil {
+			return CardCatalog{}, err
+		}
+
 		groupName := key
 		groupCards := groupData
 
@@ -420,128 +504,7 @@ func collectCards(ctx context.Context) {
 			continue
 		}
 
-		// First we create the card with the common fields
-		cardForGroup := scryfall.MTG_CardDB{
-			Layout:         scryfallModel.Layout(filteredGroupCards[0].Layout),
-			CMC:            filteredGroupCards[0].CMC,
-			ColorIdentity:  filteredGroupCards[0].ColorIdentity,
-			ColorIndicator: filteredGroupCards[0].ColorIndicator,
-			Colors:         filteredGroupCards[0].Colors,
-			EDHRecRank:     filteredGroupCards[0].EDHRecRank,
-			Keywords:       filteredGroupCards[0].Keywords,
-			Loyalty:        filteredGroupCards[0].Loyalty,
-			ManaCost:       filteredGroupCards[0].ManaCost,
-			Name:           filteredGroupCards[0].Name,
-			OracleText:     filteredGroupCards[0].OracleText,
-			Power:          filteredGroupCards[0].Power,
-			ProducedMana:   filteredGroupCards[0].ProducedMana,
-			Toughness:      filteredGroupCards[0].Toughness,
-			TypeLine:       filteredGroupCards[0].TypeLine,
-		}
-
-		// If the card has no color identity, we set it to C (Colorless)
-		if len(cardForGroup.ColorIdentity) == 0 {
-			cardForGroup.ColorIdentity = []string{"C"}
-		}
-
-		// Then we create the versions for the card, selecting the best version based on the score function to be the default
-		for _, card := range filteredGroupCards {
-			cardVersionDB := scryfall.MTG_CardVersionDB{
-				ID:         card.ID,
-				IsDefault:  false, // Will be set later by the default logic
-				IsAlchemy:  strings.HasPrefix(card.Name, "A-"),
-				Artist:     card.Artist,
-				Lang:       scryfallModel.CardLanguage(card.Lang), // Direct cast for enum
-				FlavorName: card.FlavorName,
-				FlavorText: card.FlavorText,
-				// ImageUris will be converted below
-				Legalities:      card.Legalities,
-				Games:           card.Games,
-				Name:            card.Name,
-				Rarity:          scryfallModel.Rarity(card.Rarity), // Direct cast for enum
-				ReleasedAt:      card.ReleasedAt,
-				Reprint:         card.Reprint,
-				SetName:         card.SetName,
-				SetType:         card.SetType,
-				Set:             card.Set,
-				SetID:           card.SetID,
-				Variation:       card.Variation,
-				VariationOf:     card.VariationOf,
-				Booster:         card.Booster,
-				Finishes:        card.Finishes,
-				FrameEffects:    card.FrameEffects,
-				FullArt:         card.FullArt,
-				PromoTypes:      card.PromoTypes,
-				CollectorNumber: card.CollectorNumber,
-				IllustrationID:  card.IllustrationID,
-			}
-
-			if card.PrintedName != nil {
-				cardVersionDB.PrintedName = *card.PrintedName
-			} else {
-				cardVersionDB.PrintedName = card.Name
-			}
-
-			var cardFacesDB []scryfall.MTG_CardVersionFaceDB
-			if card.CardFaces != nil {
-				for _, face := range *card.CardFaces {
-					cardFace := scryfall.MTG_CardVersionFaceDB{
-						Artist:         face.Artist,
-						CMC:            face.CMC,
-						ColorIndicator: face.ColorIndicator,
-						Colors:         face.Colors,
-						FlavorText:     face.FlavorText,
-						Loyalty:        face.Loyalty,
-						ManaCost:       face.ManaCost,
-						Name:           face.Name,
-						OracleText:     face.OracleText,
-						Power:          face.Power,
-						Toughness:      face.Toughness,
-						TypeLine:       face.TypeLine,
-						Layout:         face.Layout, // Will be converted below
-					}
-
-					if face.ImageUris != nil {
-						cardFace.ImageUris = &model.MtgImage{
-							ArtCrop:    face.ImageUris.ArtCrop,
-							BorderCrop: face.ImageUris.BorderCrop,
-							Large:      face.ImageUris.Large,
-							Normal:     face.ImageUris.Normal,
-							Small:      face.ImageUris.Small,
-							Png:        face.ImageUris.PNG,
-						}
-					}
-					cardFacesDB = append(cardFacesDB, cardFace)
-				}
-			}
-			if len(cardFacesDB) > 0 {
-				cardVersionDB.CardFaces = &cardFacesDB
-			}
-			// Assign ImageUris after potential faces processing (though source struct is flat here)
-			if card.ImageUris != nil {
-				cardVersionDB.ImageUris = &model.MtgImage{
-					ArtCrop:    card.ImageUris.ArtCrop,
-					BorderCrop: card.ImageUris.BorderCrop,
-					Large:      card.ImageUris.Large,
-					Normal:     card.ImageUris.Normal,
-					Small:      card.ImageUris.Small,
-					Png:        card.ImageUris.PNG,
-				}
-			}
-
-			cardForGroup.Versions = append(cardForGroup.Versions, cardVersionDB)
-		}
-
-		// --- Start: Logic to determine the single default version (improved) ---
-		if len(cardForGroup.Versions) > 0 {
-			bestIdx := pickDefaultIndex(cardForGroup.Versions)
-			for i := range cardForGroup.Versions {
-				cardForGroup.Versions[i].IsDefault = (i == bestIdx)
-			}
-			defaultVersion := cardForGroup.Versions[bestIdx]
-			cardForGroup.ID = normalizeCardName(defaultVersion.Name)
-		}
-		// --- End: Logic to determine the single default version (improved) ---
+		cardForGroup := buildCardGroup(filteredGroupCards)
 
 		// Find versions that are from the same set, for each of them, if they have the same illustration_id, log them in groups in the console
 		// Group by set, then by illustration_id
@@ -556,10 +519,17 @@ func collectCards(ctx context.Context) {
 			setIllustrationGroups[v.Set][*v.IllustrationID] = append(setIllustrationGroups[v.Set][*v.IllustrationID], v)
 		}
 
+		for _, card := range filteredGroupCards {
+			if card.AllParts != nil {
+				relatedParts[cardForGroup.ID] = append(relatedParts[cardForGroup.ID], *card.AllParts...)
+			}
+		}
+
 		allCardsToSave = append(allCardsToSave, cardForGroup)
 
 		// Update processed groups count
 		processedGroups++
+		m.reportGroups(processedGroups, totalGroups)
 		progress := (processedGroups * 100) / totalGroups
 		if progress >= logThreshold {
 			log.Info().Msgf("Processing progress: %d%% (%d / %d groups)", progress, processedGroups, totalGroups)
@@ -568,34 +538,262 @@ func collectCards(ctx context.Context) {
 
 	} // End group processing loop
 
-	aq = arango.NewQuery( /* aql */ `
-		FOR c IN @cards
-			UPSERT { _key: c._key }
-			INSERT MERGE({ _key: c._key }, c)
-			UPDATE c
-			IN mtg_cards
-		`)
+	log.Info().Msgf("Finished processing %d groups.", len(allGroups))
+
+	tokens, err := collectTokens(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error collecting tokens")
+		return CardCatalog{}, err
+	}
+
+	return CardCatalog{Cards: allCardsToSave, Tokens: tokens, RelatedParts: relatedParts}, nil
+}
+
+// storeCards writes a card source's catalog: the cards are diffed into mtg_cards,
+// the catalog and legality change feeds, key aliases, tokens and card relations
+// are updated, today's price snapshot is recorded when recordPrices is set, and
+// the search index is rebuilt. Only cards whose content changed are written; the
+// returned stats report what was touched. Progress is reported to m when it is
+// not nil.
+func storeCards(ctx context.Context, catalog CardCatalog, recordPrices bool, m *ImportManager) (syncStats, error) {
+	// Cancellation is honoured up to here. Writing mtg_cards, its aliases and
+	// the index runs to completion so the catalog is never left half-updated.
+	if err := ctx.Err(); err != nil {
+		return syncStats{}, err
+	}
+	ctx = context.WithoutCancel(ctx)
+	allCardsToSave := catalog.Cards
+
+	previous, err := loadCatalogSnapshots(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error loading catalog snapshots")
+		return syncStats{}, err
+	}
+	rekeySnapshots(previous, allCardsToSave)
+
+	stats, err := syncCards(ctx, arango.MTG_CARDS_COLLECTION, allCardsToSave, m)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error syncing cards")
+		return stats, err
+	}
 
-	aq.AddBindVar("cards", allCardsToSave)
+	changes, err := recordCatalogChanges(ctx, previous, allCardsToSave)
+	if err != nil {
+		log.Error().Err(err).Msg("Error recording catalog changes")
+		return stats, err
+	}
+	log.Info().Int("changes", changes).Msg("Recorded catalog changes")
 
-	_, err = arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	legalityChanges, err := recordLegalityChanges(ctx, previous, allCardsToSave)
 	if err != nil {
-		log.Error().Err(err).Msgf("Error upserting cards")
+		log.Error().Err(err).Msg("Error recording legality changes")
+		return stats, err
+	}
+	log.Info().Int("changes", legalityChanges).Msg("Recorded legality changes")
+
+	if err := updateCardKeyAliases(ctx, allCardsToSave); err != nil {
+		log.Error().Err(err).Msg("Error updating card key aliases")
+		return stats, err
+	}
+	if err := migrateCardKeyReferences(ctx); err != nil {
+		log.Error().Err(err).Msg("Error migrating card key references")
+		return stats, err
+	}
+	if err := syncTokens(ctx, catalog.Tokens); err != nil {
+		return stats, err
+	}
+	if err := syncRelatedCards(ctx, allCardsToSave, catalog.Tokens, catalog.RelatedParts); err != nil {
+		log.Error().Err(err).Msg("Error syncing related cards")
+		return stats, err
 	}
 
-	log.Info().Msgf("Finished processing %d groups. JSON saved to '%s' directory.", len(allGroups), cardsDir)
+	// The index reads current prices from the latest snapshot, so it is taken
+	// before the index is rebuilt.
+	if recordPrices {
+		if err := recordPriceSnapshot(ctx, slices.Concat(allCardsToSave, catalog.Tokens)); err != nil {
+			return stats, err
+		}
+	}
+
+	log.Info().Msgf("Finished storing %d cards: %s.", len(allCardsToSave), stats)
+
+	if m != nil {
+		m.setPhase(PhaseRebuildingIndex, fmt.Sprintf("Cards synced (%s), rebuilding search index...", stats), bandSyncEnd)
+	}
 
 	// Rebuild the card index after updating cards
 	cards, err := mtg.GetMTGCards(ctx)
 	if err != nil {
 		log.Error().Err(err).Msg("Error fetching cards")
-		return
+		return stats, err
 	}
 	err = mtgCardSearch.BuildCardIndexWithCards(cards)
 	if err != nil {
 		log.Error().Err(err).Msg("Error building card index")
-		return
+		return stats, err
 	}
+
+	return stats, nil
+}
+
+// buildCardGroup merges the printings of one card into its curated document:
+// the common fields come from the first printing, every printing becomes a
+// version, and the best version is marked as the default. The key is the
+// oracle ID, or the normalized name for cards without one.
+func buildCardGroup(filteredGroupCards []scryfall.Card) scryfall.MTG_CardDB {
+	// First we create the card with the common fields
+	cardForGroup := scryfall.MTG_CardDB{
+		Layout:         scryfallModel.Layout(filteredGroupCards[0].Layout),
+		CMC:            filteredGroupCards[0].CMC,
+		ColorIdentity:  filteredGroupCards[0].ColorIdentity,
+		ColorIndicator: filteredGroupCards[0].ColorIndicator,
+		Colors:         filteredGroupCards[0].Colors,
+		Defense:        filteredGroupCards[0].Defense,
+		EDHRecRank:     filteredGroupCards[0].EDHRecRank,
+		GameChanger:    filteredGroupCards[0].GameChanger != nil && *filteredGroupCards[0].GameChanger,
+		Keywords:       filteredGroupCards[0].Keywords,
+		Loyalty:        filteredGroupCards[0].Loyalty,
+		ManaCost:       filteredGroupCards[0].ManaCost,
+		Name:           filteredGroupCards[0].Name,
+		OracleText:     filteredGroupCards[0].OracleText,
+		PennyRank:      filteredGroupCards[0].PennyRank,
+		Power:          filteredGroupCards[0].Power,
+		ProducedMana:   filteredGroupCards[0].ProducedMana,
+		Reserved:       filteredGroupCards[0].Reserved,
+		Toughness:      filteredGroupCards[0].Toughness,
+		TypeLine:       filteredGroupCards[0].TypeLine,
+	}
+
+	// If the card has no color identity, we set it to C (Colorless)
+	if len(cardForGroup.ColorIdentity) == 0 {
+		cardForGroup.ColorIdentity = []string{"C"}
+	}
+
+	// Then we create the versions for the card, selecting the best version based on the score function to be the default
+	for _, card := range filteredGroupCards {
+		cardVersionDB := scryfall.MTG_CardVersionDB{
+			ID:         card.ID,
+			IsDefault:  false, // Will be set later by the default logic
+			IsAlchemy:  strings.HasPrefix(card.Name, "A-"),
+			Artist:     card.Artist,
+			Lang:       scryfallModel.CardLanguage(card.Lang), // Direct cast for enum
+			FlavorName: card.FlavorName,
+			FlavorText: card.FlavorText,
+			// ImageUris will be converted below
+			Legalities:      card.Legalities,
+			Games:           card.Games,
+			Name:            card.Name,
+			Rarity:          scryfallModel.Rarity(card.Rarity), // Direct cast for enum
+			ReleasedAt:      card.ReleasedAt,
+			Reprint:         card.Reprint,
+			SetName:         card.SetName,
+			SetType:         card.SetType,
+			Set:             card.Set,
+			SetID:           card.SetID,
+			Variation:       card.Variation,
+			VariationOf:     card.VariationOf,
+			Booster:         card.Booster,
+			Finishes:        card.Finishes,
+			FrameEffects:    card.FrameEffects,
+			FullArt:         card.FullArt,
+			PromoTypes:      card.PromoTypes,
+			CollectorNumber: card.CollectorNumber,
+			IllustrationID:  card.IllustrationID,
+			Prices:          convertPrices(card.Prices),
+			BorderColor:     string(card.BorderColor),
+			Frame:           card.Frame,
+			Watermark:       card.Watermark,
+			SecurityStamp:   card.SecurityStamp,
+			Textless:        card.Textless,
+			Promo:           card.Promo,
+			Digital:         card.Digital,
+			ArenaID:         card.ArenaID,
+			MtgoID:          card.MtgoID,
+			TcgplayerID:     card.TcgplayerID,
+			CardmarketID:    card.CardmarketID,
+		}
+
+		if card.PrintedName != nil {
+			cardVersionDB.PrintedName = *card.PrintedName
+		} else {
+			cardVersionDB.PrintedName = card.Name
+		}
+		cardVersionDB.PrintedText = card.PrintedText
+		cardVersionDB.PrintedTypeLine = card.PrintedTypeLine
+
+		var cardFacesDB []scryfall.MTG_CardVersionFaceDB
+		if card.CardFaces != nil {
+			for _, face := range *card.CardFaces {
+				cardFace := scryfall.MTG_CardVersionFaceDB{
+					Artist:          face.Artist,
+					CMC:             face.CMC,
+					ColorIndicator:  face.ColorIndicator,
+					Colors:          face.Colors,
+					Defense:         face.Defense,
+					FlavorText:      face.FlavorText,
+					Loyalty:         face.Loyalty,
+					ManaCost:        face.ManaCost,
+					Name:            face.Name,
+					OracleText:      face.OracleText,
+					Power:           face.Power,
+					PrintedName:     face.PrintedName,
+					PrintedText:     face.PrintedText,
+					PrintedTypeLine: face.PrintedTypeLine,
+					Toughness:       face.Toughness,
+					TypeLine:        face.TypeLine,
+					Watermark:       face.Watermark,
+					Layout:          face.Layout, // Will be converted below
+				}
+
+				if face.ImageUris != nil {
+					cardFace.ImageUris = &model.MtgImage{
+						ArtCrop:    face.ImageUris.ArtCrop,
+						BorderCrop: face.ImageUris.BorderCrop,
+						Large:      face.ImageUris.Large,
+						Normal:     face.ImageUris.Normal,
+						Small:      face.ImageUris.Small,
+						Png:        face.ImageUris.PNG,
+					}
+				}
+				cardFacesDB = append(cardFacesDB, cardFace)
+			}
+		}
+		if len(cardFacesDB) > 0 {
+			cardVersionDB.CardFaces = &cardFacesDB
+		}
+		// Assign ImageUris after potential faces processing (though source struct is flat here)
+		if card.ImageUris != nil {
+			cardVersionDB.ImageUris = &model.MtgImage{
+				ArtCrop:    card.ImageUris.ArtCrop,
+				BorderCrop: card.ImageUris.BorderCrop,
+				Large:      card.ImageUris.Large,
+				Normal:     card.ImageUris.Normal,
+				Small:      card.ImageUris.Small,
+				Png:        card.ImageUris.PNG,
+			}
+		}
+
+		cardForGroup.Versions = append(cardForGroup.Versions, cardVersionDB)
+	}
+
+	// --- Start: Logic to determine the single default version (improved) ---
+	if len(cardForGroup.Versions) > 0 {
+		rankDefaultVersions(cardForGroup.Versions)
+		bestIdx := pickDefaultIndex(cardForGroup.Versions)
+		for i := range cardForGroup.Versions {
+			cardForGroup.Versions[i].IsDefault = (i == bestIdx)
+		}
+		defaultVersion := cardForGroup.Versions[bestIdx]
+		cardForGroup.OracleID = cardOracleID(filteredGroupCards[0])
+		if cardForGroup.OracleID != "" {
+			cardForGroup.ID = cardForGroup.OracleID
+		} else {
+			cardForGroup.ID = normalizeCardName(defaultVersion.Name)
+		}
+	}
+	// --- End: Logic to determine the single default version (improved) ---
+
+	return cardForGroup
 }
 
 // has returns whether target is present in sl.
@@ -702,6 +900,22 @@ func pickDefaultIndex(versions []scryfall.MTG_CardVersionDB) int {
 	return bestIdx
 }
 
+// rankDefaultVersions stores each version's position in the defaultLess ordering,
+// so printing profiles can fall back to it when their rules leave a tie.
+func rankDefaultVersions(versions []scryfall.MTG_CardVersionDB) {
+	stats := computeGroupStats(versions)
+	order := make([]int, len(versions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return defaultLess(versions[order[i]], versions[order[j]], stats)
+	})
+	for rank, idx := range order {
+		versions[idx].DefaultRank = rank
+	}
+}
+
 // defaultLess returns true if a should be preferred as the default over b.
 func defaultLess(a, b scryfall.MTG_CardVersionDB, stats groupStats) bool {
 	// 1) Language (prefer English; future-proof when you remove AQL lang filter)
@@ -923,6 +1137,18 @@ func splitCollectorNumber(cn string) (num int, suffix string) {
 	return n, cn[i:]
 }
 
+// cardOracleID returns the oracle ID of a printing, falling back to the first
+// face for reversible cards, or "" when the printing has none.
+func cardOracleID(card scryfall.Card) string {
+	if card.OracleID != nil && *card.OracleID != "" {
+		return *card.OracleID
+	}
+	if card.CardFaces != nil && len(*card.CardFaces) > 0 {
+		return safeString((*card.CardFaces)[0].OracleID)
+	}
+	return ""
+}
+
 // normalizeCardName normalizes a card name to be used as a document key in ArangoDB.
 func normalizeCardName(name string) string {
 	// Convert to lowercase
@@ -951,56 +1177,16 @@ func normalizeCardName(name string) string {
 	return name
 }
 
-// downloadFile saves the response body to a local file under the 'cards' directory.
-func downloadFile(body io.Reader, filename string) error {
-	// Ensure the 'cards' directory exists
-	cardsDir := "cards"
-	if _, err := os.Stat(cardsDir); os.IsNotExist(err) {
-		err = os.MkdirAll(cardsDir, 0755)
-		if err != nil {
-			return err
-		}
-	}
-
-	// Create the file in the 'cards' directory
-	filePath := filepath.Join(cardsDir, filename)
-	out, err := os.Create(filePath)
-	if err != nil {
-		return err
-	}
-	defer out.Close()
-
-	// Write the body to file
-	_, err = io.Copy(out, body)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-func runMTGCardsCycle(ctx context.Context) {
-	fetched := fetchMTGCards(ctx)
-	if fetched {
-		collectCards(ctx)
-		return
-	}
-	rebuildCardIndex(ctx)
-}
-
-func rebuildCardIndex(ctx context.Context) {
+// rebuildCardIndex reloads all cards and rebuilds the in-memory search index.
+func rebuildCardIndex(ctx context.Context) error {
 	cards, err := mtg.GetMTGCards(ctx)
 	if err != nil {
 		log.Error().Err(err).Msg("Error fetching cards")
-		return
+		return err
 	}
 	if err := mtgCardSearch.BuildCardIndexWithCards(cards); err != nil {
 		log.Error().Err(err).Msg("Error building card index")
+		return err
 	}
-}
-func RunMTGCardsImport(ctx context.Context) {
-	if ctx == nil {
-		ctx = context.Background()
-	}
-	log.Info().Msg("Manual MTG cards import triggered")
-	runMTGCardsCycle(ctx)
+	return nil
 }
@@ -0,0 +1,206 @@
+package daemons
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+	"magic-helper/graph/model/scryfall"
+	"os"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+)
+
+// rulingsBulkType is the Scryfall bulk dataset holding every card ruling.
+const rulingsBulkType = "rulings"
+
+// fetchMTGRulings imports card rulings from the local file configured in source,
+// or from Scryfall's rulings bulk dataset when it changed since the last import.
+func fetchMTGRulings(ctx context.Context, source ImportSource) error {
+	if source.RulingsFile != "" {
+		log.Info().Msgf("Importing rulings from local file %s", source.RulingsFile)
+		if err := processRulingsFile(ctx, source.RulingsFile); err != nil {
+			log.Error().Err(err).Msgf("Error processing rulings from %s", source.RulingsFile)
+			return err
+		}
+		return nil
+	}
+
+	log.Info().Msg("Fetching rulings from Scryfall bulk data endpoint")
+
+	state, err := readFetchState("MTG_rulings")
+	if err != nil {
+		log.Error().Err(err).Msgf("Error reading rulings fetch state")
+		return err
+	}
+
+	bodyList, etag, changed, err := fetchBodyIfChanged(ctx, scryfallAPIURL("/bulk-data"), state.ETag)
+	if err != nil {
+		log.Error().Err(err).Msg("Error fetching bulk data list")
+		return err
+	}
+	if !changed {
+		log.Info().Msg("Bulk data list unchanged since last fetch, skipping rulings")
+		return nil
+	}
+
+	var bulkDataResponse ScryfallResponse
+	if err := json.Unmarshal(bodyList, &bulkDataResponse); err != nil {
+		log.Error().Err(err).Msgf("Error unmarshalling bulk data list response body")
+		return fmt.Errorf("decoding bulk data list: %w", err)
+	}
+
+	var bulkData ScryfallBulkData
+	for _, item := range bulkDataResponse.Data {
+		var candidate ScryfallBulkData
+		if err := json.Unmarshal(item, &candidate); err != nil {
+			log.Error().Err(err).Msgf("Error unmarshalling collection item")
+			return fmt.Errorf("decoding bulk data item: %w", err)
+		}
+		if candidate.Type == rulingsBulkType && candidate.DownloadURI != "" {
+			bulkData = candidate
+			break
+		}
+	}
+	if bulkData.DownloadURI == "" {
+		return fmt.Errorf("bulk data list has no downloadable %q entry", rulingsBulkType)
+	}
+
+	state.ETag = etag
+	if bulkData.UpdatedAt == state.BulkUpdatedAt && bulkData.Size == state.BulkSize {
+		log.Info().Str("updatedAt", bulkData.UpdatedAt).Msg("Rulings unchanged since last fetch, skipping")
+		if err := saveFetchState(state); err != nil {
+			log.Error().Err(err).Msgf("Error saving rulings fetch state")
+		}
+		return nil
+	}
+
+	var filePath string
+	err = retryWithBackoff(ctx, "download "+bulkData.DownloadURI, func() error {
+		var err error
+		filePath, err = downloadBulkFile(ctx, bulkData.DownloadURI, rulingsBulkType+".json", bulkData.Size, nil)
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error downloading rulings file from %s", bulkData.DownloadURI)
+		return err
+	}
+	if err := processRulingsFile(ctx, filePath); err != nil {
+		log.Error().Err(err).Msgf("Error processing rulings from %s", filePath)
+		return err
+	}
+
+	state.BulkType = bulkData.Type
+	state.BulkUpdatedAt = bulkData.UpdatedAt
+	state.BulkSize = bulkData.Size
+	if err := saveFetchState(state); err != nil {
+		log.Error().Err(err).Msgf("Error saving rulings fetch state")
+	}
+
+	return nil
+}
+
+// processRulingsFile decodes a Scryfall rulings array from disk, groups the
+// rulings by oracle ID and replaces the contents of mtg_card_rulings with them.
+func processRulingsFile(ctx context.Context, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		log.Error().Err(err).Msgf("Error opening rulings file %s", filePath)
+		return err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(bufio.NewReaderSize(file, 1024*1024))
+	if _, err := decoder.Token(); err != nil {
+		if err == io.EOF {
+			log.Error().Msg("Rulings file is empty.")
+		} else {
+			log.Error().Err(err).Msg("Error decoding rulings: expecting start of array `[`")
+		}
+		return err
+	}
+
+	byOracleID := map[string][]*model.MtgRuling{}
+	total := 0
+	for decoder.More() {
+		var ruling scryfall.Ruling
+		if err := decoder.Decode(&ruling); err != nil {
+			log.Error().Err(err).Msg("Error decoding ruling JSON object")
+			return err
+		}
+		if ruling.OracleID == "" || ruling.Comment == "" {
+			continue
+		}
+		byOracleID[ruling.OracleID] = append(byOracleID[ruling.OracleID], &model.MtgRuling{
+			Source:      ruling.Source,
+			PublishedAt: ruling.PublishedAt,
+			Comment:     ruling.Comment,
+		})
+		total++
+	}
+
+	// Replacing with nothing would wipe every ruling; keep the stored ones instead.
+	if total == 0 {
+		return fmt.Errorf("rulings file %s holds no rulings", filePath)
+	}
+
+	documents := make([]model.MTGCardRulingsDB, 0, len(byOracleID))
+	for oracleID, rulings := range byOracleID {
+		sort.SliceStable(rulings, func(i, j int) bool { return rulings[i].PublishedAt < rulings[j].PublishedAt })
+		documents = append(documents, model.MTGCardRulingsDB{ID: oracleID, Rulings: rulings})
+	}
+
+	if err := storeRulings(ctx, documents); err != nil {
+		return err
+	}
+
+	log.Info().Int("rulings", total).Int("cards", len(documents)).Msgf("Finished processing rulings from %s", filePath)
+	return nil
+}
+
+// storeRulings replaces the rulings of every card in documents and removes the
+// rulings of cards no longer listed.
+func storeRulings(ctx context.Context, documents []model.MTGCardRulingsDB) error {
+	batchSize := 1000
+	keys := make([]string, 0, len(documents))
+	for start := 0; start < len(documents); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch := documents[start:min(start+batchSize, len(documents))]
+		for _, document := range batch {
+			keys = append(keys, document.ID)
+		}
+
+		aq := arango.NewQuery( /* aql */ `
+			FOR r IN @rulings
+				UPSERT { _key: r._key }
+				INSERT r
+				REPLACE r
+				IN mtg_card_rulings
+		`)
+		aq.AddBindVar("rulings", batch)
+
+		if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+			log.Error().Err(err).Msg("Error storing rulings")
+			return err
+		}
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+		FOR r IN mtg_card_rulings
+			FILTER r._key NOT IN @keys
+			REMOVE r IN mtg_card_rulings
+	`)
+	aq.AddBindVar("keys", keys)
+
+	if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+		log.Error().Err(err).Msg("Error removing stale rulings")
+		return err
+	}
+	return nil
+}
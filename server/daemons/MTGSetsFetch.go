@@ -3,114 +3,68 @@ package daemons
 import (
 	"context"
 	"encoding/json"
-	"io"
+	"fmt"
 	"magic-helper/arango"
+	"magic-helper/graph/model"
 	"magic-helper/graph/model/scryfall"
-	"net/http"
 	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
-// PeriodicFetchMTGSets runs a 24h loop fetching Scryfall sets and syncing them to DB.
-func PeriodicFetchMTGSets() {
-	log.Info().Msg("Starting periodic fetch sets daemon")
-	for {
-		runMTGSetsCycle()
-		time.Sleep(24 * time.Hour)
-	}
-}
-
-func runMTGSetsCycle() {
-	ctx := context.Background()
-	if fetchSets(ctx) {
-		updateDatabaseSets()
-	}
-}
-
-// fetchSets downloads all paginated Scryfall sets, stores originals, downloads icons,
-// and updates the last-fetched timestamp.
-func fetchSets(ctx context.Context) bool {
-	log.Info().Msg("Fetching sets from Scryfall")
-	url := "https://api.scryfall.com/sets"
-
-	// Check if we should fetch sets
-	shouldFetch := true
-	var err error
-	shouldFetch, err = shouldDownloadStart("MTG_sets")
-	if err != nil {
-		log.Error().Err(err).Msgf("Error checking if we should fetch sets")
-		return false
-	}
-
-	if !shouldFetch {
-		return false
-	}
-
+// fetchSets loads sets either from the local file configured in source or from the
+// paginated Scryfall API and stores the originals. For downloads it returns the
+// fetch state holding the response ETag, to be saved once the sets are stored. It
+// returns false without an error when Scryfall reports the sets unchanged since
+// the last fetch. The number of sets stored is reported to m when it is not nil.
+func fetchSets(ctx context.Context, source ImportSource, m *ImportManager) ([]model.MTGApplicationConfig, bool, error) {
 	var allSets []json.RawMessage
-	pagesFetched := 0
-
-	i := 1
-	for {
-		// Fetch the data from the API
-		req, err := createScryfallRequest(url)
+	var state model.MTGApplicationConfig
+	if source.SetsFile != "" {
+		log.Info().Msgf("Reading sets from local file %s", source.SetsFile)
+		var err error
+		allSets, err = readLocalSetsFile(source.SetsFile)
 		if err != nil {
-			log.Error().Err(err).Msgf("Error creating request to Scryfall")
-			return false
+			log.Error().Err(err).Msgf("Error reading sets file %s", source.SetsFile)
+			return nil, false, err
 		}
-
-		resp, err := http.DefaultClient.Do(req)
+	} else {
+		var changed bool
+		var err error
+		state, err = readFetchState("MTG_sets")
 		if err != nil {
-			log.Error().Err(err).Msgf("Error fetching sets from Scryfall")
-			return false
+			log.Error().Err(err).Msgf("Error reading sets fetch state")
+			return nil, false, err
 		}
-
-		if resp.StatusCode != http.StatusOK {
-			log.Error().Msgf("Error fetching sets from Scryfall: %v", resp.Status)
-			return false
+		allSets, changed, err = downloadSets(ctx, &state)
+		if err != nil || !changed {
+			return nil, false, err
 		}
-
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			log.Error().Err(err).Msgf("Error reading response body")
-			return false
-		}
-
-		// Unmarshal the JSON
-		var response ScryfallResponse
-		err = json.Unmarshal(body, &response)
-		if err != nil {
-			log.Error().Err(err).Msgf("Error unmarshalling response body")
-			return false
-		}
-
-		allSets = append(allSets, response.Data...)
-		pagesFetched++
-
-		if !response.HasMore {
-			break
-		}
-
-		log.Info().Msgf("Fetched page %v", i)
-		i++
-		url = response.NextPage
-		time.Sleep(100 * time.Millisecond)
 	}
 
 	log.Info().Msgf("Fetched %v sets", len(allSets))
 
+	// Malformed sets are quarantined so the rest of the sets are still imported.
+	runID := m.runID()
 	sets := []scryfall.Set{}
+	var quarantined []model.MTGQuarantinedRecordDB
 	for _, set := range allSets {
 		var setMap scryfall.Set
 		err := json.Unmarshal(set, &setMap)
+		if err == nil {
+			err = validateSet(setMap)
+		}
 		if err != nil {
 			log.Error().Err(err).Str("set", string(set)).Msgf("Error unmarshalling set")
-			return false
+			quarantined = append(quarantined, quarantineRecord(model.MtgQuarantineKindSet, probeRecordID(set), set, err, runID))
+			continue
 		}
 		sets = append(sets, setMap)
 	}
+	if err := storeQuarantined(ctx, quarantined, m); err != nil {
+		return nil, false, err
+	}
 
 	log.Info().Msgf("Unmarshalled %v sets", len(sets))
 	log.Info().Msgf("Inserting sets into database")
@@ -126,30 +80,89 @@ func fetchSets(ctx context.Context) bool {
 
 	aq.AddBindVar("sets", sets)
 
-	_, err = arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	_, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error inserting sets into database")
-		return false
+		return nil, false, err
 	}
 
-	// Update the last time we fetched sets
-	err = updateLastTimeFetched("MTG_sets")
-	if err != nil {
-		log.Error().Err(err).Msgf("Error updating last time fetched")
-		return false
+	// Local snapshots don't count as a fetch, so the next download isn't skipped.
+	var states []model.MTGApplicationConfig
+	if source.SetsFile == "" {
+		states = append(states, state)
 	}
 
+	m.reportSets(len(sets))
 	log.Info().Msgf("Inserted sets into database")
 	log.Info().Msgf("Done")
 
-	return true
+	return states, true, nil
 }
 
-// updateDatabaseSets transforms original sets to the app schema and upserts into MTG_Sets.
-func updateDatabaseSets() {
-	ctx := context.Background()
-	log.Info().Msg("Updating database sets")
+// downloadSets fetches every page of the Scryfall sets endpoint, retrying
+// transient failures. The first page is requested conditionally on state.ETag;
+// when Scryfall answers 304 Not Modified it returns false without an error.
+// Otherwise state.ETag is updated to the new response's ETag.
+func downloadSets(ctx context.Context, state *model.MTGApplicationConfig) ([]json.RawMessage, bool, error) {
+	log.Info().Msg("Fetching sets from Scryfall")
+	url := scryfallAPIURL("/sets")
+
+	var allSets []json.RawMessage
+	pagesFetched := 0
+
+	i := 1
+	for {
+		// Fetch the data from the API. Scryfall serves every set on the first
+		// page, so its ETag tells whether anything changed.
+		var body []byte
+		var err error
+		if i == 1 {
+			var etag string
+			var changed bool
+			body, etag, changed, err = fetchBodyIfChanged(ctx, url, state.ETag)
+			if err == nil && !changed {
+				log.Info().Msg("Sets unchanged since last fetch, skipping")
+				return nil, false, nil
+			}
+			state.ETag = etag
+		} else {
+			body, err = fetchBodyWithContext(ctx, url)
+		}
+		if err != nil {
+			log.Error().Err(err).Msgf("Error fetching sets from Scryfall")
+			return nil, true, err
+		}
+
+		// Unmarshal the JSON
+		var response ScryfallResponse
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error unmarshalling response body")
+			return nil, true, fmt.Errorf("decoding sets page %d: %w", i, err)
+		}
+
+		allSets = append(allSets, response.Data...)
+		pagesFetched++
 
+		if !response.HasMore {
+			break
+		}
+
+		log.Info().Msgf("Fetched page %v", i)
+		i++
+		url = response.NextPage
+		select {
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	return allSets, true, nil
+}
+
+// collectSets transforms the original sets to the app schema.
+func collectSets(ctx context.Context) ([]scryfall.MTG_SetDB, error) {
 	aq := arango.NewQuery( /* aql */ `
 		FOR s IN mtg_original_sets
 			RETURN s
@@ -158,7 +171,7 @@ func updateDatabaseSets() {
 	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error querying database")
-		return
+		return nil, err
 	}
 	defer cursor.Close()
 
@@ -169,7 +182,7 @@ func updateDatabaseSets() {
 		_, err := cursor.ReadDocument(ctx, &set)
 		if err != nil {
 			log.Error().Err(err).Msgf("Error reading document")
-			return
+			return nil, err
 		}
 		sets = append(sets, set)
 	}
@@ -202,22 +215,57 @@ func updateDatabaseSets() {
 			SearchURI:     set.SearchURI,
 		}
 	}
+	return dbSets, nil
+}
+
+// updateDatabaseSets upserts the sets of a card source into mtg_sets, returning
+// how many sets were added, changed or left unchanged.
+func updateDatabaseSets(ctx context.Context, dbSets []scryfall.MTG_SetDB) (syncStats, error) {
+	var stats syncStats
+	log.Info().Msg("Updating database sets")
 
-	aq = arango.NewQuery( /* aql */ `
-		FOR s IN @sets
-			UPSERT { _key: s._key }
-			INSERT MERGE({ _key: s._key }, s)
-			UPDATE s
-			IN mtg_sets
+	aq := arango.NewQuery( /* aql */ `
+		LET results = (
+			FOR s IN @sets
+				UPSERT { _key: s._key }
+				INSERT MERGE({ _key: s._key }, s)
+				UPDATE s
+				IN mtg_sets
+				RETURN {
+					added: OLD == null,
+					changed: OLD != null AND UNSET(OLD, "_rev") != UNSET(NEW, "_rev")
+				}
+		)
+		RETURN {
+			inserted: LENGTH(results[* FILTER CURRENT.added]),
+			updated: LENGTH(results[* FILTER CURRENT.changed]),
+			total: LENGTH(results)
+		}
 	`)
 
 	aq.AddBindVar("sets", dbSets)
 
-	_, err = arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error updating database sets")
+		return stats, err
+	}
+	defer cursor.Close()
+
+	var counts struct {
+		Inserted int `json:"inserted"`
+		Updated  int `json:"updated"`
+		Total    int `json:"total"`
+	}
+	if _, err := cursor.ReadDocument(ctx, &counts); err != nil {
+		log.Error().Err(err).Msgf("Error reading set update counts")
+		return stats, err
 	}
+	stats.Inserted = counts.Inserted
+	stats.Updated = counts.Updated
+	stats.Unchanged = counts.Total - counts.Inserted - counts.Updated
 
-	log.Info().Msgf("Updated database sets")
+	log.Info().Msgf("Updated database sets: %s", stats)
 	log.Info().Msgf("Done")
+	return stats, nil
 }
@@ -0,0 +1,228 @@
+package daemons
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+)
+
+// bulkDownloadDir is where bulk files and their partial downloads are kept.
+const bulkDownloadDir = "cards"
+
+// bulkFileMeta is stored next to a bulk file (or its partial download) so the
+// transfer can be resumed against the same remote file, and a completed file can
+// be reused as long as it is unchanged on disk.
+type bulkFileMeta struct {
+	URL    string `json:"url"`
+	ETag   string `json:"etag,omitempty"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// downloadBulkFile streams downloadURI into the bulk directory under filename and
+// returns the path of the downloaded file.
+//
+// The body is written to "<filename>.part" first. If a partial file for the same
+// URL already exists the transfer resumes from its end with a Range request
+// (guarded by If-Range so a republished file restarts from zero). Once complete,
+// the size is checked against expectedSize or, when that is unknown, against the
+// Content-Length of the response. The SHA-256 of the finished file is recorded
+// only to notice later changes on disk: a previously completed file whose
+// checksum still matches is reused without downloading again. onProgress, if
+// set, receives the bytes downloaded so far and the total size.
+func downloadBulkFile(ctx context.Context, downloadURI string, filename string, expectedSize int64, onProgress func(read, total int64)) (string, error) {
+	if err := os.MkdirAll(bulkDownloadDir, 0755); err != nil {
+		return "", err
+	}
+
+	finalPath := filepath.Join(bulkDownloadDir, filename)
+	partPath := finalPath + ".part"
+
+	// Reuse a completed download of the same file if it is still intact.
+	if meta, err := readBulkFileMeta(finalPath); err == nil && meta.URL == downloadURI {
+		sum, size, err := fileSHA256(finalPath)
+		if err == nil && sum == meta.SHA256 && (expectedSize <= 0 || size == expectedSize) {
+			log.Info().Str("file", finalPath).Msg("Bulk file already downloaded and unchanged, skipping download")
+			if onProgress != nil {
+				onProgress(size, size)
+			}
+			return finalPath, nil
+		}
+		log.Warn().Str("file", finalPath).Msg("Existing bulk file changed on disk, downloading again")
+	}
+
+	var offset int64
+	partMeta, err := readBulkFileMeta(partPath)
+	if info, statErr := os.Stat(partPath); statErr == nil && err == nil && partMeta.URL == downloadURI {
+		offset = info.Size()
+	}
+
+	req, err := createScryfallRequestWithContext(ctx, downloadURI)
+	if err != nil {
+		return "", err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if partMeta.ETag != "" {
+			req.Header.Set("If-Range", partMeta.ETag)
+		}
+	}
+
+	resp, err := doScryfallRequest(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		log.Info().Int64("offset", offset).Msgf("Resuming download of %s", downloadURI)
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		if offset > 0 {
+			log.Info().Msgf("Remote file changed or ranges unsupported, restarting download of %s", downloadURI)
+		}
+		offset = 0
+		flags |= os.O_TRUNC
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file may already hold the whole body, but only a known
+		// size can tell; otherwise it is discarded and downloaded again.
+		if expectedSize <= 0 {
+			log.Warn().Int64("offset", offset).Msgf("Cannot check partial download of unknown size, restarting download of %s", downloadURI)
+			resp.Body.Close()
+			os.Remove(partPath)
+			os.Remove(bulkFileMetaPath(partPath))
+			return downloadBulkFile(ctx, downloadURI, filename, expectedSize, onProgress)
+		}
+		log.Info().Int64("offset", offset).Msg("Partial download already complete")
+	default:
+		return "", newHTTPStatusError(downloadURI, resp)
+	}
+
+	// The ETag of the response identifies the file being written; a resumed
+	// download keeps the one it was started with.
+	etag := partMeta.ETag
+	wantSize := expectedSize
+	if resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		if respETag := resp.Header.Get("ETag"); respETag != "" {
+			etag = respETag
+		} else if resp.StatusCode == http.StatusOK {
+			etag = ""
+		}
+		if wantSize <= 0 && resp.ContentLength > 0 {
+			wantSize = offset + resp.ContentLength
+		}
+		if err := writeBulkFileMeta(partPath, bulkFileMeta{URL: downloadURI, ETag: etag, Size: expectedSize}); err != nil {
+			return "", err
+		}
+
+		out, err := os.OpenFile(partPath, flags, 0644)
+		if err != nil {
+			return "", err
+		}
+
+		totalSize := expectedSize
+		if resp.ContentLength > 0 {
+			totalSize = offset + resp.ContentLength
+		}
+		if totalSize > 0 {
+			log.Info().Msgf("Starting download of card data file (%d bytes)...", totalSize)
+		} else {
+			log.Info().Msg("Starting download of card data file (size unknown)...")
+		}
+
+		progressReader := NewProgressReader(resp.Body, totalSize)
+		progressReader.ReadSoFar = offset
+		progressReader.NextLogAt = offset + progressReader.LogInterval
+		progressReader.OnProgress = onProgress
+
+		_, copyErr := io.Copy(out, progressReader)
+		closeErr := out.Close()
+		if copyErr != nil {
+			// Keep the partial file so the next attempt can resume it.
+			return "", copyErr
+		}
+		if closeErr != nil {
+			return "", closeErr
+		}
+	}
+
+	sum, size, err := fileSHA256(partPath)
+	if err != nil {
+		return "", err
+	}
+	if wantSize > 0 && size != wantSize {
+		// A size mismatch means the partial data cannot be trusted; start over next time.
+		os.Remove(partPath)
+		os.Remove(bulkFileMetaPath(partPath))
+		return "", fmt.Errorf("downloaded %d bytes but expected %d", size, wantSize)
+	}
+
+	if err := os.Rename(partPath, finalPath); err != nil {
+		return "", err
+	}
+	os.Remove(bulkFileMetaPath(partPath))
+
+	if err := writeBulkFileMeta(finalPath, bulkFileMeta{URL: downloadURI, ETag: etag, Size: size, SHA256: sum}); err != nil {
+		return "", err
+	}
+
+	log.Info().Str("file", finalPath).Int64("bytes", size).Str("sha256", sum).Msg("Bulk file downloaded")
+	return finalPath, nil
+}
+
+// bulkFileMetaPath returns the path of the metadata file kept next to path.
+func bulkFileMetaPath(path string) string {
+	return path + ".meta"
+}
+
+// readBulkFileMeta loads the metadata stored next to path.
+func readBulkFileMeta(path string) (bulkFileMeta, error) {
+	var meta bulkFileMeta
+	data, err := os.ReadFile(bulkFileMetaPath(path))
+	if err != nil {
+		return meta, err
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return meta, err
+	}
+	if meta.URL == "" {
+		return meta, errors.New("bulk file metadata has no URL")
+	}
+	return meta, nil
+}
+
+// writeBulkFileMeta stores the metadata next to path.
+func writeBulkFileMeta(path string, meta bulkFileMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(bulkFileMetaPath(path), data, 0644)
+}
+
+// fileSHA256 returns the hex SHA-256 digest and size of the file at path.
+func fileSHA256(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
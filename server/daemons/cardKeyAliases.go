@@ -0,0 +1,196 @@
+package daemons
+
+import (
+	"context"
+	"magic-helper/arango"
+	"magic-helper/graph/model/scryfall"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// cardKeyReference names a collection attribute that stores a card _id.
+type cardKeyReference struct {
+	Collection string
+	Attribute  string
+}
+
+// cardKeyReferences lists every edge attribute that points at mtg_cards. Card
+// keys stored inside documents, the deck zone children and the printing profile
+// pins, are rewritten by migrateCardKeyReferences itself.
+var cardKeyReferences = []cardKeyReference{
+	{Collection: arango.MTG_CARD_DECK_EDGE.String(), Attribute: "_from"},
+	{Collection: arango.MTG_IGNORED_CARDS_EDGE_COLLECTION.String(), Attribute: "_to"},
+	{Collection: arango.MTG_DECK_FRONT_IMAGE_EDGE.String(), Attribute: "_to"},
+	{Collection: arango.MTG_TAG_TO_CARD_EDGE.String(), Attribute: "_to"},
+	{Collection: arango.MTG_CARD_IN_CARD_PACKAGE_EDGE.String(), Attribute: "_from"},
+}
+
+// legacyCardKey returns the name-based key a card had before cards were grouped
+// by oracle ID, or "" when the card never had one of its own.
+func legacyCardKey(card scryfall.MTG_CardDB) string {
+	for _, version := range card.Versions {
+		if !version.IsDefault {
+			continue
+		}
+		// Alchemy printings used to be merged into the paper card's group.
+		if version.IsAlchemy || strings.HasPrefix(version.Name, "A-") {
+			return ""
+		}
+		return normalizeCardName(version.Name)
+	}
+	return ""
+}
+
+// cardKeyAliases maps the old name-based key of every card that now lives under
+// a different key to its current key.
+func cardKeyAliases(cards []scryfall.MTG_CardDB) []scryfall.MTG_CardKeyAliasDB {
+	// Sort so that name collisions between different oracle cards always resolve
+	// to the same card.
+	sorted := make([]scryfall.MTG_CardDB, len(cards))
+	copy(sorted, cards)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	aliases := make([]scryfall.MTG_CardKeyAliasDB, 0)
+	seen := make(map[string]string)
+	for _, card := range sorted {
+		oldKey := legacyCardKey(card)
+		if oldKey == "" || oldKey == card.ID {
+			continue
+		}
+		if existing, dup := seen[oldKey]; dup {
+			// The old key merged several cards; prefer the one whose full name matches.
+			if normalizeCardName(card.Name) != oldKey {
+				continue
+			}
+			log.Debug().Str("alias", oldKey).Str("card", card.ID).Str("replaces", existing).Msg("Card key alias collision")
+			for i := range aliases {
+				if aliases[i].ID == oldKey {
+					aliases[i].CardKey = card.ID
+				}
+			}
+			seen[oldKey] = card.ID
+			continue
+		}
+		seen[oldKey] = card.ID
+		aliases = append(aliases, scryfall.MTG_CardKeyAliasDB{ID: oldKey, CardKey: card.ID})
+	}
+	return aliases
+}
+
+// updateCardKeyAliases records the old name-based key of every card that now
+// lives under a different key. Existing aliases are kept so references that are
+// still around keep resolving.
+func updateCardKeyAliases(ctx context.Context, cards []scryfall.MTG_CardDB) error {
+	aliases := cardKeyAliases(cards)
+	for start := 0; start < len(aliases); start += cardSyncBatchSize {
+		batch := aliases[start:min(start+cardSyncBatchSize, len(aliases))]
+		aq := arango.NewQuery( /* aql */ `
+			FOR a IN @aliases
+				UPSERT { _key: a._key }
+				INSERT a
+				REPLACE a
+				IN mtg_card_key_aliases
+		`)
+		aq.AddBindVar("aliases", batch)
+		if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+			return err
+		}
+	}
+
+	log.Info().Msgf("Recorded %d card key aliases", len(aliases))
+	return nil
+}
+
+// migrateCardKeyReferences rewrites deck, tag, ignore and package edges, deck
+// zone children and printing profile pins that still use an aliased card key.
+// Keys that exist in mtg_cards are never rewritten, so running it repeatedly is
+// safe.
+func migrateCardKeyReferences(ctx context.Context) error {
+	for _, ref := range cardKeyReferences {
+		aq := arango.NewQuery( /* aql */ `
+			LET migrated = (
+				FOR e IN @@edges
+					LET target = PARSE_IDENTIFIER(e[@attribute])
+					FILTER target.collection == "mtg_cards"
+					LET alias = DOCUMENT("mtg_card_key_aliases", target.key)
+					FILTER alias != null
+					FILTER DOCUMENT("mtg_cards", target.key) == null
+					UPDATE e WITH { [@attribute]: CONCAT("mtg_cards/", alias.cardKey) } IN @@edges
+					RETURN 1
+			)
+			RETURN LENGTH(migrated)
+		`)
+		aq.AddBindVar("@edges", ref.Collection)
+		aq.AddBindVar("attribute", ref.Attribute)
+
+		cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+		if err != nil {
+			log.Error().Err(err).Msgf("Error migrating card keys in %s", ref.Collection)
+			return err
+		}
+		var migrated int
+		for cursor.HasMore() {
+			if _, err := cursor.ReadDocument(ctx, &migrated); err != nil {
+				cursor.Close()
+				return err
+			}
+		}
+		cursor.Close()
+		if migrated > 0 {
+			log.Info().Msgf("Migrated %d card references in %s", migrated, ref.Collection)
+		}
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+		FOR d IN mtg_decks
+			FILTER IS_ARRAY(d.zones)
+			LET zones = (
+				FOR z IN d.zones
+					RETURN MERGE(z, {
+						cardChildren: (
+							FOR child IN z.cardChildren || []
+								LET alias = DOCUMENT("mtg_card_key_aliases", child)
+								RETURN alias != null AND DOCUMENT("mtg_cards", child) == null ? alias.cardKey : child
+						)
+					})
+			)
+			FILTER zones != d.zones
+			UPDATE d WITH { zones: zones } IN mtg_decks
+	`)
+
+	_, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("Error migrating card keys in deck zones")
+		return err
+	}
+
+	// A card pinned under both its old and its current key keeps the pin made
+	// under the current key.
+	aq = arango.NewQuery( /* aql */ `
+		FOR profile IN mtg_printing_profiles
+			FILTER IS_ARRAY(profile.pins)
+			LET pins = (
+				FOR pin IN profile.pins
+					LET alias = DOCUMENT("mtg_card_key_aliases", pin.cardID)
+					LET moved = alias != null AND DOCUMENT("mtg_cards", pin.cardID) == null
+					RETURN { pin: moved ? MERGE(pin, { cardID: alias.cardKey }) : pin, moved }
+			)
+			LET migrated = (
+				FOR p IN pins
+					FILTER !p.moved OR LENGTH(pins[* FILTER !CURRENT.moved AND CURRENT.pin.cardID == p.pin.cardID]) == 0
+					RETURN p.pin
+			)
+			FILTER migrated != profile.pins
+			UPDATE profile WITH { pins: migrated } IN mtg_printing_profiles
+	`)
+
+	_, err = arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("Error migrating card keys in printing profile pins")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package daemons
+
+import (
+	"context"
+	"magic-helper/graph/model"
+	"magic-helper/graph/model/scryfall"
+	"magic-helper/settings"
+)
+
+// CardSource is a provider of set and card data. Implementations translate their
+// provider's format into the app's MTG_SetDB and MTG_CardDB records, so
+// everything after fetching (the mtg_sets and mtg_cards diffs, change feeds,
+// aliases, relations and the search index) works the same for every provider.
+type CardSource interface {
+	// Name is the provider name shown in import messages.
+	Name() string
+	// FetchSets returns the provider's sets. It returns false without an error
+	// when the sets are unchanged since the last import.
+	FetchSets(ctx context.Context, source ImportSource, m *ImportManager) (SetCatalog, bool, error)
+	// FetchCards returns the provider's card catalog. It returns false without an
+	// error when the cards are unchanged since the last import.
+	FetchCards(ctx context.Context, source ImportSource, m *ImportManager) (CardCatalog, bool, error)
+	// FetchStates lists the application_config records the source keeps its
+	// fetch state in, which a forced import resets.
+	FetchStates() []string
+	// ProvidesPrices reports whether the cards carry prices for the daily price
+	// snapshot.
+	ProvidesPrices() bool
+	// ProvidesRulings reports whether the source stores rulings itself, in which
+	// case they are not fetched from Scryfall.
+	ProvidesRulings() bool
+}
+
+// SetCatalog holds the sets a card source produced and the fetch state to save
+// once they are stored.
+type SetCatalog struct {
+	Sets        []scryfall.MTG_SetDB
+	FetchStates []model.MTGApplicationConfig
+}
+
+// CardCatalog holds the cards and tokens a card source produced, the all_parts
+// relations of each card keyed by card key, and the fetch state to save once
+// the catalog is stored.
+type CardCatalog struct {
+	Cards        []scryfall.MTG_CardDB
+	Tokens       []scryfall.MTG_CardDB
+	RelatedParts map[string][]scryfall.RelatedCard
+	FetchStates  []model.MTGApplicationConfig
+}
+
+// currentCardSource returns a new instance of the card source selected by
+// import.cardSource.
+func currentCardSource() CardSource {
+	if settings.Current.Import.CardSource == "mtgjson" {
+		return &mtgjsonSource{}
+	}
+	return scryfallSource{}
+}
+
+// scryfallSource imports Scryfall's set list and card bulk data. Originals are
+// stored in mtg_original_sets and mtg_original_cards and grouped from there.
+type scryfallSource struct{}
+
+// Name implements CardSource.
+func (scryfallSource) Name() string {
+	return "Scryfall"
+}
+
+// FetchSets implements CardSource.
+func (scryfallSource) FetchSets(ctx context.Context, source ImportSource, m *ImportManager) (SetCatalog, bool, error) {
+	states, updated, err := fetchSets(ctx, source, m)
+	if err != nil || !updated {
+		return SetCatalog{}, false, err
+	}
+	sets, err := collectSets(ctx)
+	if err != nil {
+		return SetCatalog{}, false, err
+	}
+	return SetCatalog{Sets: sets, FetchStates: states}, true, nil
+}
+
+// FetchCards implements CardSource.
+func (scryfallSource) FetchCards(ctx context.Context, source ImportSource, m *ImportManager) (CardCatalog, bool, error) {
+	states, fetched, err := fetchMTGCards(ctx, source, m)
+	if err != nil || !fetched {
+		return CardCatalog{}, false, err
+	}
+	if m != nil {
+		m.setPhase(PhaseProcessingCards, "Processing and grouping cards...", bandUpsertEnd)
+	}
+	catalog, err := collectCards(ctx, m)
+	if err != nil {
+		return CardCatalog{}, false, err
+	}
+	catalog.FetchStates = states
+	return catalog, true, nil
+}
+
+// FetchStates implements CardSource.
+func (scryfallSource) FetchStates() []string {
+	return []string{"MTG_sets", "MTG_cards"}
+}
+
+// ProvidesPrices implements CardSource.
+func (scryfallSource) ProvidesPrices() bool {
+	return true
+}
+
+// ProvidesRulings implements CardSource. Rulings are a separate Scryfall bulk
+// file, fetched by the import manager.
+func (scryfallSource) ProvidesRulings() bool {
+	return false
+}
@@ -0,0 +1,212 @@
+package daemons
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"magic-helper/arango"
+	"magic-helper/graph/model/scryfall"
+
+	"github.com/rs/zerolog/log"
+)
+
+// cardSyncBatchSize bounds how many documents are written per AQL query.
+const cardSyncBatchSize = 1000
+
+// syncStats counts how a rebuild changed a collection such as mtg_cards or mtg_sets.
+type syncStats struct {
+	Inserted  int
+	Updated   int
+	Removed   int
+	Unchanged int
+}
+
+// String formats the counts for logs and status messages.
+func (s syncStats) String() string {
+	return fmt.Sprintf("%d inserted, %d updated, %d removed, %d unchanged", s.Inserted, s.Updated, s.Removed, s.Unchanged)
+}
+
+// hashCard returns a SHA-256 over the card's content, ignoring any stored hash
+// and the EDHREC and Penny Dreadful ranks, which shift with every import and are
+// written on their own. Prices are not part of the stored card at all.
+func hashCard(card scryfall.MTG_CardDB) (string, error) {
+	card.ContentHash = ""
+	card.EDHRecRank = nil
+	card.PennyRank = nil
+	data, err := json.Marshal(card)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// storedCard is the part of a stored card syncCards compares against.
+type storedCard struct {
+	Key        string `json:"key"`
+	Hash       string `json:"hash"`
+	EDHRecRank *int   `json:"EDHRecRank"`
+	PennyRank  *int   `json:"pennyRank"`
+}
+
+// loadCardHashes returns the content hash and ranks of every card stored in
+// collection, keyed by _key. Cards written before hashes existed map to an empty
+// hash and are rewritten.
+func loadCardHashes(ctx context.Context, collection arango.ArangoDocument) (map[string]storedCard, error) {
+	aq := arango.NewQuery( /* aql */ `
+		FOR c IN @@collection
+			RETURN { key: c._key, hash: c.contentHash, EDHRecRank: c.EDHRecRank, pennyRank: c.pennyRank }
+	`)
+	aq.AddBindVar("@collection", collection.String())
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	hashes := make(map[string]storedCard)
+	for cursor.HasMore() {
+		var entry storedCard
+		if _, err := cursor.ReadDocument(ctx, &entry); err != nil {
+			return nil, err
+		}
+		hashes[entry.Key] = entry
+	}
+
+	return hashes, nil
+}
+
+// syncCards brings a curated collection (mtg_cards or mtg_tokens) in line with
+// cards, writing only the groups whose content changed. New groups are
+// inserted, changed ones replaced and groups that no longer exist removed, so
+// readers never see an empty catalog. Unchanged groups whose ranks moved only
+// get their ranks updated and still count as unchanged.
+func syncCards(ctx context.Context, collection arango.ArangoDocument, cards []scryfall.MTG_CardDB, m *ImportManager) (syncStats, error) {
+	var stats syncStats
+
+	stored, err := loadCardHashes(ctx, collection)
+	if err != nil {
+		log.Error().Err(err).Msg("Error loading stored card hashes")
+		return stats, err
+	}
+
+	var toInsert, toReplace []scryfall.MTG_CardDB
+	var rankUpdates []storedCard
+	seen := make(map[string]struct{}, len(cards))
+	for _, card := range cards {
+		if _, dup := seen[card.ID]; dup {
+			log.Warn().Str("key", card.ID).Msg("Duplicate card key in rebuild, keeping first group")
+			continue
+		}
+		seen[card.ID] = struct{}{}
+
+		hash, err := hashCard(card)
+		if err != nil {
+			log.Error().Err(err).Str("key", card.ID).Msg("Error hashing card")
+			return stats, err
+		}
+		card.ContentHash = hash
+
+		old, exists := stored[card.ID]
+		switch {
+		case !exists:
+			toInsert = append(toInsert, card)
+		case old.Hash != hash:
+			toReplace = append(toReplace, card)
+		default:
+			stats.Unchanged++
+			if !sameRank(old.EDHRecRank, card.EDHRecRank) || !sameRank(old.PennyRank, card.PennyRank) {
+				rankUpdates = append(rankUpdates, storedCard{Key: card.ID, EDHRecRank: card.EDHRecRank, PennyRank: card.PennyRank})
+			}
+		}
+	}
+
+	var toRemove []string
+	for key := range stored {
+		if _, ok := seen[key]; !ok {
+			toRemove = append(toRemove, key)
+		}
+	}
+
+	pending := len(toInsert) + len(toReplace) + len(toRemove)
+	m.reportSync(stats, pending)
+
+	for start := 0; start < len(toInsert); start += cardSyncBatchSize {
+		batch := toInsert[start:min(start+cardSyncBatchSize, len(toInsert))]
+		aq := arango.NewQuery( /* aql */ `
+			FOR c IN @cards
+				INSERT c INTO @@collection
+		`)
+		aq.AddBindVar("@collection", collection.String())
+		aq.AddBindVar("cards", batch)
+		if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+			log.Error().Err(err).Msg("Error inserting cards")
+			return stats, err
+		}
+		stats.Inserted += len(batch)
+		m.reportSync(stats, pending)
+	}
+
+	for start := 0; start < len(toReplace); start += cardSyncBatchSize {
+		batch := toReplace[start:min(start+cardSyncBatchSize, len(toReplace))]
+		aq := arango.NewQuery( /* aql */ `
+			FOR c IN @cards
+				REPLACE c IN @@collection
+		`)
+		aq.AddBindVar("@collection", collection.String())
+		aq.AddBindVar("cards", batch)
+		if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+			log.Error().Err(err).Msg("Error replacing cards")
+			return stats, err
+		}
+		stats.Updated += len(batch)
+		m.reportSync(stats, pending)
+	}
+
+	for start := 0; start < len(toRemove); start += cardSyncBatchSize {
+		batch := toRemove[start:min(start+cardSyncBatchSize, len(toRemove))]
+		aq := arango.NewQuery( /* aql */ `
+			FOR key IN @keys
+				REMOVE key IN @@collection
+		`)
+		aq.AddBindVar("@collection", collection.String())
+		aq.AddBindVar("keys", batch)
+		if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+			log.Error().Err(err).Msg("Error removing cards")
+			return stats, err
+		}
+		stats.Removed += len(batch)
+		m.reportSync(stats, pending)
+	}
+
+	for start := 0; start < len(rankUpdates); start += cardSyncBatchSize {
+		batch := rankUpdates[start:min(start+cardSyncBatchSize, len(rankUpdates))]
+		aq := arango.NewQuery( /* aql */ `
+			FOR r IN @ranks
+				UPDATE r.key WITH { EDHRecRank: r.EDHRecRank, pennyRank: r.pennyRank } IN @@collection
+				OPTIONS { keepNull: false }
+		`)
+		aq.AddBindVar("@collection", collection.String())
+		aq.AddBindVar("ranks", batch)
+		if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+			log.Error().Err(err).Msg("Error updating card ranks")
+			return stats, err
+		}
+	}
+	if len(rankUpdates) > 0 {
+		log.Info().Int("cards", len(rankUpdates)).Msg("Updated ranks of unchanged cards")
+	}
+
+	return stats, nil
+}
+
+// sameRank reports whether two optional ranks are equal.
+func sameRank(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
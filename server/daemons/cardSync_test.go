@@ -0,0 +1,76 @@
+package daemons
+
+import (
+	"magic-helper/graph/model"
+	"magic-helper/graph/model/scryfall"
+	"testing"
+)
+
+// hashTestCard returns a card with one priced version, built fresh for every
+// case so mutations don't leak between them.
+func hashTestCard() scryfall.MTG_CardDB {
+	text := "Haste"
+	usd := 0.25
+	edhrec, penny := 120, 40
+	return scryfall.MTG_CardDB{
+		ID:         "c56fbd5c-2d0c-4c77-9f2a-5d1b4e3c9a11",
+		Name:       "Goblin Guide",
+		TypeLine:   "Creature — Goblin Scout",
+		OracleText: &text,
+		EDHRecRank: &edhrec,
+		PennyRank:  &penny,
+		Versions: []scryfall.MTG_CardVersionDB{{
+			ID:        "6c5f4e2b-8f0d-4c1f-a5c4-3b1f2e9d7a20",
+			Set:       "zen",
+			IsDefault: true,
+			Prices:    &model.MtgPrices{Usd: &usd},
+		}},
+	}
+}
+
+func TestHashCard(t *testing.T) {
+	base, err := hashCard(hashTestCard())
+	if err != nil {
+		t.Fatalf("hashCard: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(card *scryfall.MTG_CardDB)
+		changed bool
+	}{
+		{name: "stored hash", mutate: func(card *scryfall.MTG_CardDB) { card.ContentHash = "stale" }},
+		{name: "price", mutate: func(card *scryfall.MTG_CardDB) {
+			usd := 0.5
+			card.Versions[0].Prices = &model.MtgPrices{Usd: &usd}
+		}},
+		{name: "price removed", mutate: func(card *scryfall.MTG_CardDB) { card.Versions[0].Prices = nil }},
+		{name: "EDHREC rank", mutate: func(card *scryfall.MTG_CardDB) {
+			rank := 121
+			card.EDHRecRank = &rank
+		}},
+		{name: "Penny Dreadful rank removed", mutate: func(card *scryfall.MTG_CardDB) { card.PennyRank = nil }},
+		{name: "oracle text", changed: true, mutate: func(card *scryfall.MTG_CardDB) {
+			text := "Haste\nWhenever Goblin Guide attacks, defending player reveals the top card of their library."
+			card.OracleText = &text
+		}},
+		{name: "type line", changed: true, mutate: func(card *scryfall.MTG_CardDB) { card.TypeLine = "Creature — Goblin" }},
+		{name: "default version", changed: true, mutate: func(card *scryfall.MTG_CardDB) { card.Versions[0].IsDefault = false }},
+		{name: "new version", changed: true, mutate: func(card *scryfall.MTG_CardDB) {
+			card.Versions = append(card.Versions, scryfall.MTG_CardVersionDB{ID: "0e9b7a3c-1d2f-4b5a-8c6d-7e8f9a0b1c2d", Set: "m12"})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := hashTestCard()
+			tt.mutate(&card)
+			got, err := hashCard(card)
+			if err != nil {
+				t.Fatalf("hashCard: %v", err)
+			}
+			if (got != base) != tt.changed {
+				t.Errorf("hash changed = %v, want %v", got != base, tt.changed)
+			}
+		})
+	}
+}
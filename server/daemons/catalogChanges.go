@@ -0,0 +1,238 @@
+package daemons
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+	"magic-helper/graph/model/scryfall"
+
+	"github.com/rs/zerolog/log"
+)
+
+// catalogSnapshot holds the fields of a stored card that the catalog and legality
+// change feeds compare against the next import.
+type catalogSnapshot struct {
+	OracleText *string
+	TypeLine   *string
+	ManaCost   *string
+	Legalities map[string]string
+	VersionIDs map[string]struct{}
+}
+
+// loadCatalogSnapshots returns a snapshot of every card in mtg_cards, keyed by
+// _key. It must run before the catalog is rewritten.
+func loadCatalogSnapshots(ctx context.Context) (map[string]catalogSnapshot, error) {
+	aq := arango.NewQuery( /* aql */ `
+		FOR c IN mtg_cards
+			LET defaultVersion = FIRST(c.versions[* FILTER CURRENT.isDefault])
+			LET faces = defaultVersion.cardFaces
+			RETURN {
+				key: c._key,
+				oracleText: c.oracleText,
+				typeLine: c.typeLine,
+				manaCost: c.manaCost,
+				faces: faces[* RETURN { oracleText: CURRENT.oracleText, manaCost: CURRENT.manaCost }],
+				legalities: defaultVersion.legalities,
+				versionIDs: c.versions[*].ID
+			}
+	`)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	snapshots := make(map[string]catalogSnapshot)
+	for cursor.HasMore() {
+		var stored struct {
+			Key        string                           `json:"key"`
+			OracleText *string                          `json:"oracleText"`
+			TypeLine   string                           `json:"typeLine"`
+			ManaCost   *string                          `json:"manaCost"`
+			Faces      []scryfall.MTG_CardVersionFaceDB `json:"faces"`
+			Legalities map[string]string                `json:"legalities"`
+			VersionIDs []string                         `json:"versionIDs"`
+		}
+		if _, err := cursor.ReadDocument(ctx, &stored); err != nil {
+			return nil, err
+		}
+		snapshot := catalogSnapshot{
+			OracleText: catalogOracleText(stored.OracleText, stored.Faces),
+			TypeLine:   nonEmpty(stored.TypeLine),
+			ManaCost:   catalogManaCost(stored.ManaCost, stored.Faces),
+			Legalities: stored.Legalities,
+			VersionIDs: make(map[string]struct{}, len(stored.VersionIDs)),
+		}
+		for _, id := range stored.VersionIDs {
+			snapshot.VersionIDs[id] = struct{}{}
+		}
+		snapshots[stored.Key] = snapshot
+	}
+
+	return snapshots, nil
+}
+
+// rekeySnapshots moves the snapshots of cards stored under their old name-based
+// key to the key they are rebuilt under, so the import that re-keys the catalog
+// compares every card with its own earlier state. Otherwise each card would look
+// new to the change feed and have no legality history to compare with.
+func rekeySnapshots(previous map[string]catalogSnapshot, cards []scryfall.MTG_CardDB) {
+	current := make(map[string]struct{}, len(cards))
+	for _, card := range cards {
+		current[card.ID] = struct{}{}
+	}
+
+	moved := 0
+	for _, alias := range cardKeyAliases(cards) {
+		if _, ok := previous[alias.CardKey]; ok {
+			continue
+		}
+		snapshot, ok := previous[alias.ID]
+		if !ok {
+			continue
+		}
+		previous[alias.CardKey] = snapshot
+		if _, taken := current[alias.ID]; !taken {
+			delete(previous, alias.ID)
+		}
+		moved++
+	}
+	if moved > 0 {
+		log.Info().Int("cards", moved).Msg("Matched snapshots stored under old card keys")
+	}
+}
+
+// recordCatalogChanges compares the rebuilt cards with the snapshots taken before
+// the rebuild (matched to their current keys by rekeySnapshots) and stores one
+// mtg_catalog_changes document per card that is new, had its oracle text, type
+// line or mana cost changed, or gained printings. An empty previous catalog is a
+// first import, where every card would count as new, so nothing is recorded.
+func recordCatalogChanges(ctx context.Context, previous map[string]catalogSnapshot, cards []scryfall.MTG_CardDB) (int, error) {
+	if len(previous) == 0 {
+		log.Info().Msg("No previous catalog, skipping change feed")
+		return 0, nil
+	}
+
+	importedAt := time.Now().UTC().Format(time.RFC3339)
+	changes := make([]model.MTGCatalogChangeDB, 0)
+	for _, card := range cards {
+		change := model.MTGCatalogChangeDB{
+			CardID:       card.ID,
+			Name:         card.Name,
+			ImportedAt:   importedAt,
+			FieldChanges: []*model.MtgCatalogFieldChange{},
+			NewVersions:  []*model.MtgCatalogNewVersion{},
+		}
+
+		old, exists := previous[card.ID]
+		if !exists {
+			change.Added = true
+			changes = append(changes, change)
+			continue
+		}
+
+		faces := defaultVersionFaces(card)
+		change.FieldChanges = appendFieldChange(change.FieldChanges, model.MtgCatalogFieldOracleText, old.OracleText, catalogOracleText(card.OracleText, faces))
+		change.FieldChanges = appendFieldChange(change.FieldChanges, model.MtgCatalogFieldTypeLine, old.TypeLine, nonEmpty(card.TypeLine))
+		change.FieldChanges = appendFieldChange(change.FieldChanges, model.MtgCatalogFieldManaCost, old.ManaCost, catalogManaCost(card.ManaCost, faces))
+
+		for _, v := range card.Versions {
+			if _, known := old.VersionIDs[v.ID]; known {
+				continue
+			}
+			change.NewVersions = append(change.NewVersions, &model.MtgCatalogNewVersion{
+				ID:              v.ID,
+				Set:             v.Set,
+				SetName:         v.SetName,
+				CollectorNumber: v.CollectorNumber,
+				ReleasedAt:      v.ReleasedAt,
+			})
+		}
+		sort.SliceStable(change.NewVersions, func(i, j int) bool {
+			return change.NewVersions[i].ReleasedAt > change.NewVersions[j].ReleasedAt
+		})
+
+		if len(change.FieldChanges) > 0 || len(change.NewVersions) > 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	for start := 0; start < len(changes); start += cardSyncBatchSize {
+		batch := changes[start:min(start+cardSyncBatchSize, len(changes))]
+		aq := arango.NewQuery( /* aql */ `
+			FOR change IN @changes
+				INSERT change INTO mtg_catalog_changes
+		`)
+		aq.AddBindVar("changes", batch)
+		if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(changes), nil
+}
+
+// appendFieldChange appends a change of field to changes when before and after differ.
+func appendFieldChange(changes []*model.MtgCatalogFieldChange, field model.MtgCatalogField, before, after *string) []*model.MtgCatalogFieldChange {
+	if safeString(before) == safeString(after) {
+		return changes
+	}
+	return append(changes, &model.MtgCatalogFieldChange{Field: field, Before: before, After: after})
+}
+
+// defaultVersion returns the card's default version, or nil when none is marked.
+func defaultVersion(card scryfall.MTG_CardDB) *scryfall.MTG_CardVersionDB {
+	for i := range card.Versions {
+		if card.Versions[i].IsDefault {
+			return &card.Versions[i]
+		}
+	}
+	return nil
+}
+
+// defaultVersionFaces returns the faces of the card's default version, if any.
+func defaultVersionFaces(card scryfall.MTG_CardDB) []scryfall.MTG_CardVersionFaceDB {
+	if v := defaultVersion(card); v != nil && v.CardFaces != nil {
+		return *v.CardFaces
+	}
+	return nil
+}
+
+// catalogOracleText returns the card's oracle text or, for multi-faced cards that
+// only carry it on their faces, the face texts joined like Scryfall joins names.
+func catalogOracleText(oracleText *string, faces []scryfall.MTG_CardVersionFaceDB) *string {
+	if oracleText != nil || len(faces) == 0 {
+		return oracleText
+	}
+	texts := make([]string, 0, len(faces))
+	for _, face := range faces {
+		texts = append(texts, safeString(face.OracleText))
+	}
+	return nonEmpty(strings.Join(texts, "\n//\n"))
+}
+
+// catalogManaCost returns the card's mana cost, falling back to the face costs
+// for multi-faced cards.
+func catalogManaCost(manaCost *string, faces []scryfall.MTG_CardVersionFaceDB) *string {
+	if manaCost != nil || len(faces) == 0 {
+		return manaCost
+	}
+	costs := make([]string, 0, len(faces))
+	for _, face := range faces {
+		costs = append(costs, face.ManaCost)
+	}
+	return nonEmpty(strings.Join(costs, " // "))
+}
+
+// nonEmpty returns a pointer to s, or nil when s is empty.
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,167 @@
+package daemons
+
+import (
+	"context"
+	"errors"
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ImportTrigger names what started an import run.
+type ImportTrigger string
+
+const (
+	ImportTriggerManual    ImportTrigger = "manual"
+	ImportTriggerScheduled ImportTrigger = "scheduled"
+)
+
+// importRun collects the phase timings and change counts of one import run and
+// stores them in mtg_import_runs when the run finishes. All methods are no-ops
+// on a nil run.
+type importRun struct {
+	mu           sync.Mutex
+	id           string
+	record       model.MTGImportRunDB
+	started      time.Time
+	phaseStarted time.Time
+}
+
+// newImportRunID returns a new run ID. IDs are the start time in nanoseconds, so
+// they sort in start order and serve as mtg_import_runs keys.
+func newImportRunID() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
+// newImportRun starts recording a run started by trigger.
+func newImportRun(trigger ImportTrigger) *importRun {
+	now := time.Now().UTC()
+	id := newImportRunID()
+	return &importRun{
+		id: id,
+		record: model.MTGImportRunDB{
+			ID:        &id,
+			Trigger:   string(trigger),
+			StartedAt: now.Format(time.RFC3339),
+			Phases:    []model.MTGImportPhaseTimingDB{},
+		},
+		started: now,
+	}
+}
+
+// startPhase ends the running phase, if any, and starts timing phase.
+func (r *importRun) startPhase(phase ImportPhase) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	r.endPhase(now)
+	r.record.Phases = append(r.record.Phases, model.MTGImportPhaseTimingDB{
+		Phase:     string(phase),
+		StartedAt: now.Format(time.RFC3339),
+	})
+	r.phaseStarted = now
+}
+
+// endPhase records the duration of the running phase. r.mu must be held.
+func (r *importRun) endPhase(now time.Time) {
+	if len(r.record.Phases) == 0 {
+		return
+	}
+	last := &r.record.Phases[len(r.record.Phases)-1]
+	if last.DurationMs == 0 {
+		last.DurationMs = now.Sub(r.phaseStarted).Milliseconds()
+	}
+}
+
+// setSets records how the run changed mtg_sets.
+func (r *importRun) setSets(stats syncStats) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.record.Sets = stats.toCounts()
+	r.mu.Unlock()
+}
+
+// setCards records how the run changed mtg_cards.
+func (r *importRun) setCards(stats syncStats) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.record.Cards = stats.toCounts()
+	r.mu.Unlock()
+}
+
+// setOriginalsPruned records how many original printings the run pruned.
+func (r *importRun) setOriginalsPruned(count int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.record.OriginalsPruned = count
+	r.mu.Unlock()
+}
+
+// setRecordsQuarantined records how many malformed records the run quarantined.
+func (r *importRun) setRecordsQuarantined(count int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.record.RecordsQuarantined = count
+	r.mu.Unlock()
+}
+
+// finish stores the run as complete, as cancelled when err is context.Canceled,
+// or as failed in the running phase for any other error. Saving uses its own
+// context so a cancelled import is still recorded.
+func (r *importRun) finish(err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	now := time.Now().UTC()
+	r.endPhase(now)
+	r.record.FinishedAt = now.Format(time.RFC3339)
+	r.record.DurationMs = now.Sub(r.started).Milliseconds()
+	r.record.Status = string(PhaseComplete)
+	if errors.Is(err, context.Canceled) {
+		r.record.Status = string(PhaseCancelled)
+	} else if err != nil {
+		r.record.Status = string(PhaseFailed)
+		r.record.Error = err.Error()
+		if len(r.record.Phases) > 0 {
+			r.record.FailedPhase = r.record.Phases[len(r.record.Phases)-1].Phase
+		}
+	}
+	record := r.record
+	r.mu.Unlock()
+
+	aq := arango.NewQuery( /* aql */ `
+		INSERT @run INTO mtg_import_runs
+	`)
+	aq.AddBindVar("run", record)
+
+	if _, err := arango.DB.Query(context.Background(), aq.Query, aq.BindVars); err != nil {
+		log.Error().Err(err).Msg("Error saving import run")
+	}
+}
+
+// toCounts converts the stats into their persisted form.
+func (s syncStats) toCounts() model.MTGImportCountsDB {
+	return model.MTGImportCountsDB{
+		Added:     s.Inserted,
+		Updated:   s.Updated,
+		Removed:   s.Removed,
+		Unchanged: s.Unchanged,
+	}
+}
@@ -2,6 +2,9 @@ package daemons
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -13,26 +16,48 @@ import (
 type ImportPhase string
 
 const (
-	PhaseIdle             ImportPhase = "idle"
-	PhaseResettingTimers  ImportPhase = "resetting_timers"
-	PhaseFetchingSets     ImportPhase = "fetching_sets"
-	PhaseProcessingSets   ImportPhase = "processing_sets"
-	PhaseFetchingCards    ImportPhase = "fetching_cards"
-	PhaseProcessingCards  ImportPhase = "processing_cards"
-	PhaseRebuildingIndex  ImportPhase = "rebuilding_index"
-	PhaseComplete         ImportPhase = "complete"
-	PhaseFailed           ImportPhase = "failed"
+	PhaseIdle            ImportPhase = "idle"
+	PhaseResettingTimers ImportPhase = "resetting_timers"
+	PhaseFetchingSets    ImportPhase = "fetching_sets"
+	PhaseProcessingSets  ImportPhase = "processing_sets"
+	PhaseFetchingCards   ImportPhase = "fetching_cards"
+	PhaseProcessingCards ImportPhase = "processing_cards"
+	PhaseRebuildingIndex ImportPhase = "rebuilding_index"
+	PhaseComplete        ImportPhase = "complete"
+	PhaseFailed          ImportPhase = "failed"
+	PhaseCancelled       ImportPhase = "cancelled"
 )
 
+// ImportCounters holds the running totals of an import.
+type ImportCounters struct {
+	SetsProcessed   int   `json:"setsProcessed"`
+	BytesDownloaded int64 `json:"bytesDownloaded"`
+	BytesTotal      int64 `json:"bytesTotal"`
+	CardsProcessed  int   `json:"cardsProcessed"`
+	BatchesUpserted int   `json:"batchesUpserted"`
+	GroupsProcessed int   `json:"groupsProcessed"`
+	GroupsTotal     int   `json:"groupsTotal"`
+	CardsInserted   int   `json:"cardsInserted"`
+	CardsUpdated    int   `json:"cardsUpdated"`
+	CardsRemoved    int   `json:"cardsRemoved"`
+	OriginalsPruned int   `json:"originalsPruned"`
+	// RecordsQuarantined counts the malformed records set aside in mtg_quarantine.
+	RecordsQuarantined int `json:"recordsQuarantined"`
+}
+
 // ImportStatus represents the current status of an import operation.
 type ImportStatus struct {
-	InProgress   bool        `json:"inProgress"`
-	Phase        ImportPhase `json:"phase"`
-	PhaseMessage string      `json:"phaseMessage"`
-	Progress     int         `json:"progress"`     // 0-100 percentage
-	StartedAt    *time.Time  `json:"startedAt"`
-	CompletedAt  *time.Time  `json:"completedAt"`
-	Error        string      `json:"error"`
+	InProgress   bool           `json:"inProgress"`
+	Phase        ImportPhase    `json:"phase"`
+	PhaseMessage string         `json:"phaseMessage"`
+	Progress     int            `json:"progress"` // 0-100 percentage
+	StartedAt    *time.Time     `json:"startedAt"`
+	CompletedAt  *time.Time     `json:"completedAt"`
+	Error        string         `json:"error"`
+	FailedPhase  ImportPhase    `json:"failedPhase"` // phase that was running when the import failed
+	Counters     ImportCounters `json:"counters"`
+	ETASeconds   *int           `json:"etaSeconds"` // estimate for the current step, nil when unknown
+	NextRun      *time.Time     `json:"nextRun"`    // next scheduled import, nil when none is planned
 }
 
 // ImportManager handles manual import triggers and prevents concurrent imports.
@@ -44,9 +69,26 @@ type ImportManager struct {
 	lastStarted  time.Time
 	lastFinished time.Time
 	lastError    string
+	failedPhase  ImportPhase
+	counters     ImportCounters
+	step         string
+	stepStarted  time.Time
+	eta          time.Duration
+	run          *importRun
+	cancel       context.CancelFunc
+	nextRun      time.Time
 	mu           sync.RWMutex
 }
 
+// Progress bands (in percent) of the steps that report fine-grained progress.
+const (
+	bandDownloadStart = 20
+	bandDownloadEnd   = 40
+	bandUpsertEnd     = 60
+	bandGroupsEnd     = 85
+	bandSyncEnd       = 95
+)
+
 var globalImportManager = &ImportManager{
 	phase: PhaseIdle,
 }
@@ -72,6 +114,13 @@ func (m *ImportManager) GetStatus() ImportStatus {
 		PhaseMessage: m.phaseMessage,
 		Progress:     m.progress,
 		Error:        m.lastError,
+		FailedPhase:  m.failedPhase,
+		Counters:     m.counters,
+	}
+
+	if m.importing.Load() && m.eta > 0 {
+		eta := int(m.eta.Seconds())
+		status.ETASeconds = &eta
 	}
 
 	if !m.lastStarted.IsZero() {
@@ -80,97 +129,366 @@ func (m *ImportManager) GetStatus() ImportStatus {
 	if !m.lastFinished.IsZero() {
 		status.CompletedAt = &m.lastFinished
 	}
+	if !m.nextRun.IsZero() {
+		status.NextRun = &m.nextRun
+	}
 
 	return status
 }
 
+// setNextRun records when the scheduler starts the next import; the zero time
+// means none is planned.
+func (m *ImportManager) setNextRun(next time.Time) {
+	m.mu.Lock()
+	m.nextRun = next
+	m.mu.Unlock()
+}
+
 // setPhase updates the current phase and message.
 func (m *ImportManager) setPhase(phase ImportPhase, message string, progress int) {
 	m.mu.Lock()
 	m.phase = phase
 	m.phaseMessage = message
 	m.progress = progress
+	m.step = ""
+	m.eta = 0
+	run := m.run
 	m.mu.Unlock()
+	if phase != PhaseComplete {
+		run.startPhase(phase)
+	}
 	log.Info().Str("phase", string(phase)).Int("progress", progress).Msg(message)
 }
 
-// TriggerImport starts a background import if one isn't already running.
+// fail ends the import in PhaseFailed, recording err together with the phase
+// that was running. An import stopped by CancelImport ends in PhaseCancelled.
+func (m *ImportManager) fail(err error) {
+	m.mu.Lock()
+	failedPhase := m.phase
+	during := strings.ReplaceAll(string(failedPhase), "_", " ")
+	if errors.Is(err, context.Canceled) {
+		m.phase = PhaseCancelled
+		m.phaseMessage = fmt.Sprintf("Import cancelled while %s", during)
+	} else {
+		m.failedPhase = failedPhase
+		m.lastError = fmt.Sprintf("%s: %v", failedPhase, err)
+		m.phase = PhaseFailed
+		m.phaseMessage = fmt.Sprintf("Import failed while %s", during)
+	}
+	m.step = ""
+	m.eta = 0
+	run := m.run
+	m.mu.Unlock()
+	run.finish(err)
+	if errors.Is(err, context.Canceled) {
+		log.Warn().Str("phase", string(failedPhase)).Msg("Import cancelled")
+		return
+	}
+	log.Error().Err(err).Str("phase", string(failedPhase)).Msg("Import failed")
+}
+
+// CancelImport stops a running import. The import notices the cancellation at
+// its next network request or batch and ends in PhaseCancelled; once it has
+// started writing mtg_cards it finishes that step first so the catalog stays
+// consistent. Returns (cancelled, message).
+func (m *ImportManager) CancelImport() (bool, string) {
+	if !m.importing.Load() {
+		return false, "No import in progress"
+	}
+
+	m.mu.Lock()
+	cancel := m.cancel
+	m.mu.Unlock()
+	if cancel == nil {
+		return false, "No import in progress"
+	}
+
+	cancel()
+	log.Info().Msg("Import cancellation requested")
+	return true, "Import cancellation requested"
+}
+
+// updateStep applies update to the counters and maps done/total of the named step
+// onto the lo-hi progress band, estimating the time left from the step's rate so
+// far. All report methods are no-ops on a nil manager so the import code can also
+// run without tracking progress.
+func (m *ImportManager) updateStep(step string, lo, hi int, done, total int64, update func(c *ImportCounters)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.step != step {
+		m.step = step
+		m.stepStarted = time.Now()
+		m.eta = 0
+	}
+	if update != nil {
+		update(&m.counters)
+	}
+	if total <= 0 {
+		return
+	}
+	if done > total {
+		done = total
+	}
+	m.progress = lo + int(int64(hi-lo)*done/total)
+	if done > 0 {
+		elapsed := time.Since(m.stepStarted)
+		m.eta = time.Duration(float64(elapsed) * float64(total-done) / float64(done))
+	}
+}
+
+// reportSets records the number of sets stored.
+func (m *ImportManager) reportSets(count int) {
+	if m == nil {
+		return
+	}
+	m.updateStep("sets", 0, 0, 0, 0, func(c *ImportCounters) { c.SetsProcessed = count })
+}
+
+// reportDownload records how many bytes of the bulk card file have been downloaded.
+func (m *ImportManager) reportDownload(read, total int64) {
+	if m == nil {
+		return
+	}
+	m.updateStep("download", bandDownloadStart, bandDownloadEnd, read, total, func(c *ImportCounters) {
+		c.BytesDownloaded = read
+		c.BytesTotal = total
+	})
+}
+
+// reportCardBatch records a batch of original cards upserted while read of total
+// bytes of the bulk file have been consumed.
+func (m *ImportManager) reportCardBatch(cards int, read, total int64) {
+	if m == nil {
+		return
+	}
+	m.updateStep("upsert", bandDownloadEnd, bandUpsertEnd, read, total, func(c *ImportCounters) {
+		c.CardsProcessed += cards
+		c.BatchesUpserted++
+	})
+}
+
+// reportGroups records how many card groups have been built out of total.
+func (m *ImportManager) reportGroups(done, total int) {
+	if m == nil {
+		return
+	}
+	m.updateStep("groups", bandUpsertEnd, bandGroupsEnd, int64(done), int64(total), func(c *ImportCounters) {
+		c.GroupsProcessed = done
+		c.GroupsTotal = total
+	})
+}
+
+// reportSync records the changes written to mtg_cards out of total pending writes.
+func (m *ImportManager) reportSync(stats syncStats, total int) {
+	if m == nil {
+		return
+	}
+	written := stats.Inserted + stats.Updated + stats.Removed
+	m.updateStep("sync", bandGroupsEnd, bandSyncEnd, int64(written), int64(total), func(c *ImportCounters) {
+		c.CardsInserted = stats.Inserted
+		c.CardsUpdated = stats.Updated
+		c.CardsRemoved = stats.Removed
+	})
+}
+
+// reportPruned records how many original printings were pruned.
+func (m *ImportManager) reportPruned(count int) {
+	if m == nil {
+		return
+	}
+	m.updateStep("prune", 0, 0, 0, 0, func(c *ImportCounters) { c.OriginalsPruned = count })
+	m.mu.RLock()
+	run := m.run
+	m.mu.RUnlock()
+	run.setOriginalsPruned(count)
+}
+
+// reportQuarantined adds count malformed records to those set aside in quarantine.
+func (m *ImportManager) reportQuarantined(count int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.counters.RecordsQuarantined += count
+	total := m.counters.RecordsQuarantined
+	run := m.run
+	m.mu.Unlock()
+	run.setRecordsQuarantined(total)
+}
+
+// runID returns the ID of the running import, which tags the originals it
+// upserts. Without a manager or run a fresh ID is returned.
+func (m *ImportManager) runID() string {
+	if m != nil {
+		m.mu.RLock()
+		run := m.run
+		m.mu.RUnlock()
+		if run != nil {
+			return run.id
+		}
+	}
+	return newImportRunID()
+}
+
+// TriggerImport starts a background import from source if one isn't already running.
 // Returns (started, message, inProgress).
-func (m *ImportManager) TriggerImport() (bool, string, bool) {
-	if !m.importing.CompareAndSwap(false, true) {
+func (m *ImportManager) TriggerImport(source ImportSource) (bool, string, bool) {
+	if err := source.Validate(); err != nil {
+		return false, err.Error(), m.importing.Load()
+	}
+
+	if _, ok := m.start(source, ImportTriggerManual); !ok {
 		return false, "Import already in progress", true
 	}
 
+	return true, "Import started successfully", true
+}
+
+// start runs an import from source in the background unless one is already
+// running, so manual and scheduled imports never overlap. The returned channel
+// receives the outcome (nil on success) once the import has finished.
+func (m *ImportManager) start(source ImportSource, trigger ImportTrigger) (<-chan error, bool) {
+	if !m.importing.CompareAndSwap(false, true) {
+		return nil, false
+	}
+
 	m.mu.Lock()
 	m.lastStarted = time.Now()
 	m.lastFinished = time.Time{}
 	m.lastError = ""
+	m.failedPhase = ""
+	m.counters = ImportCounters{}
+	m.run = newImportRun(trigger)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
 	m.mu.Unlock()
 
+	done := make(chan error, 1)
 	go func() {
-		defer func() {
-			m.importing.Store(false)
-			m.mu.Lock()
-			m.lastFinished = time.Now()
-			m.mu.Unlock()
-		}()
-
-		log.Info().Msg("Manual import started")
-		ctx := context.Background()
-
-		// Phase 1: Reset timestamps (5%)
-		m.setPhase(PhaseResettingTimers, "Resetting fetch timestamps...", 5)
-		if err := resetLastTimeFetched("MTG_sets"); err != nil {
-			log.Error().Err(err).Msg("Error resetting sets timestamp, continuing anyway")
+		log.Info().Str("trigger", string(trigger)).Msg("Import started")
+
+		// Manual imports re-import everything; scheduled ones skip unchanged data.
+		err := m.runImport(ctx, source, trigger == ImportTriggerManual)
+		if err != nil {
+			m.fail(err)
 		}
-		if err := resetLastTimeFetched("MTG_cards"); err != nil {
-			log.Error().Err(err).Msg("Error resetting cards timestamp, continuing anyway")
+
+		cancel()
+		m.mu.Lock()
+		m.cancel = nil
+		m.lastFinished = time.Now()
+		m.mu.Unlock()
+		m.importing.Store(false)
+		done <- err
+	}()
+
+	return done, true
+}
+
+// runImport runs the import phases in order: sets, cards, then the search index.
+// With force it first forgets the fetch state so unchanged data is imported too.
+func (m *ImportManager) runImport(ctx context.Context, source ImportSource, force bool) error {
+	cardSource := currentCardSource()
+
+	// Phase 1: Forget what was fetched so everything is imported again (5%)
+	if force {
+		m.setPhase(PhaseResettingTimers, "Resetting fetch state...", 5)
+		for _, record := range append(cardSource.FetchStates(), "MTG_rulings") {
+			if err := resetFetchState(record); err != nil {
+				log.Error().Err(err).Str("record", record).Msg("Error resetting fetch state, continuing anyway")
+			}
 		}
+	}
 
-		// Phase 2: Fetch sets (10%)
-		m.setPhase(PhaseFetchingSets, "Fetching sets from Scryfall...", 10)
-		setsUpdated := fetchSets(ctx)
+	// Phase 2: Fetch sets (10%)
+	if source.SetsFile != "" {
+		m.setPhase(PhaseFetchingSets, "Reading sets from local file...", 10)
+	} else {
+		m.setPhase(PhaseFetchingSets, fmt.Sprintf("Fetching sets from %s...", cardSource.Name()), 10)
+	}
+	setCatalog, setsUpdated, err := cardSource.FetchSets(ctx, source, m)
+	if err != nil {
+		return err
+	}
 
-		// Phase 3: Process sets (15%)
-		if setsUpdated {
-			m.setPhase(PhaseProcessingSets, "Processing sets...", 15)
-			updateDatabaseSets()
+	// Phase 3: Process sets (15%)
+	if setsUpdated {
+		m.setPhase(PhaseProcessingSets, "Processing sets...", 15)
+		setStats, err := updateDatabaseSets(ctx, setCatalog.Sets)
+		m.run.setSets(setStats)
+		if err != nil {
+			return err
 		}
+		commitFetchStates(setCatalog.FetchStates)
+	}
 
-		// Phase 4: Fetch cards (20-60%)
-		m.setPhase(PhaseFetchingCards, "Fetching cards from Scryfall...", 20)
-		cardsFetched := fetchMTGCardsWithProgress(ctx, m)
+	// Icons are mirrored even when the sets are unchanged so missing files are
+	// filled in; offline imports can't reach Scryfall's CDN.
+	if source.SetsFile == "" {
+		if err := mirrorSetIcons(ctx); err != nil {
+			return err
+		}
+	}
 
-		// Phase 5: Process cards (60-90%)
-		if cardsFetched {
-			m.setPhase(PhaseProcessingCards, "Processing and grouping cards...", 60)
-			collectCardsWithProgress(ctx, m)
+	// Rulings are stored before the cards so the index rebuilt below includes them.
+	// Offline imports only load them from a local file, and sources with their own
+	// rulings store them while fetching cards. A failure keeps the stored rulings
+	// and is retried on the next import; only cancellation stops the run.
+	if source.RulingsFile != "" || (source.CardsFile == "" && !cardSource.ProvidesRulings()) {
+		if source.RulingsFile != "" {
+			m.setPhase(PhaseFetchingCards, "Reading rulings from local file...", bandDownloadStart)
 		} else {
-			// Still rebuild the index even if we didn't fetch new cards
-			m.setPhase(PhaseRebuildingIndex, "Rebuilding search index...", 90)
-			rebuildCardIndex(ctx)
+			m.setPhase(PhaseFetchingCards, "Fetching rulings from Scryfall...", bandDownloadStart)
 		}
+		if err := fetchMTGRulings(ctx, source); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			log.Warn().Err(err).Msg("Error importing rulings, keeping the stored ones")
+		}
+	}
 
-		// Complete
-		m.setPhase(PhaseComplete, "Import completed successfully", 100)
-		log.Info().Msg("Manual import completed")
-	}()
+	// Phase 4: Fetch cards (20-40% download, 40-60% upsert)
+	if source.CardsFile != "" {
+		m.setPhase(PhaseFetchingCards, "Reading cards from local file...", bandDownloadEnd)
+	} else {
+		m.setPhase(PhaseFetchingCards, fmt.Sprintf("Fetching cards from %s...", cardSource.Name()), bandDownloadStart)
+	}
+	// The source moves on to grouping (60-85%) itself once its data is read.
+	catalog, cardsFetched, err := cardSource.FetchCards(ctx, source, m)
+	if err != nil {
+		return err
+	}
 
-	return true, "Import started successfully", true
-}
+	// Phase 5: Process cards (85-95% writing changes)
+	completeMessage := "Import completed successfully"
+	if cardsFetched {
+		// Local snapshots carry the prices of the day they were taken, not today's,
+		// and sources without prices would record empty snapshots.
+		recordPrices := source.CardsFile == "" && cardSource.ProvidesPrices()
+		stats, err := storeCards(ctx, catalog, recordPrices, m)
+		m.run.setCards(stats)
+		if err != nil {
+			return err
+		}
+		commitFetchStates(catalog.FetchStates)
+		completeMessage = fmt.Sprintf("Import completed successfully: %s, %d stale printings pruned", stats, m.GetStatus().Counters.OriginalsPruned)
+	} else {
+		// Still rebuild the index even if we didn't fetch new cards
+		m.setPhase(PhaseRebuildingIndex, "Rebuilding search index...", bandSyncEnd)
+		if err := rebuildCardIndex(ctx); err != nil {
+			return err
+		}
+	}
 
-// fetchMTGCardsWithProgress fetches cards and updates progress.
-func fetchMTGCardsWithProgress(ctx context.Context, m *ImportManager) bool {
-	// This wraps the existing fetch logic but updates progress
-	// For now, we'll use the existing function and update progress at key points
-	return fetchMTGCards(ctx)
-}
+	if quarantined := m.GetStatus().Counters.RecordsQuarantined; quarantined > 0 {
+		completeMessage += fmt.Sprintf(" (%d malformed records quarantined)", quarantined)
+	}
 
-// collectCardsWithProgress processes cards and updates progress.
-func collectCardsWithProgress(ctx context.Context, m *ImportManager) {
-	m.setPhase(PhaseProcessingCards, "Clearing old card data...", 65)
-	// The collectCards function handles its own progress logging
-	// We'll update the phase at the start
-	collectCards(ctx)
-	m.setPhase(PhaseRebuildingIndex, "Rebuilding search index...", 95)
+	// Complete
+	m.run.finish(nil)
+	m.setPhase(PhaseComplete, completeMessage, 100)
+	log.Info().Msg("Import completed")
+	return nil
 }
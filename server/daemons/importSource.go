@@ -0,0 +1,128 @@
+package daemons
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"magic-helper/settings"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ImportSource selects where an import reads its data from. Empty paths mean the
+// corresponding data is downloaded from the card source. With the MTGJSON card
+// source CardsFile is an AllPrintings file, which also provides the sets.
+type ImportSource struct {
+	SetsFile    string
+	CardsFile   string
+	RulingsFile string
+}
+
+// DefaultImportSource returns the import source configured in the settings file.
+func DefaultImportSource() ImportSource {
+	return ImportSource{
+		SetsFile:    settings.Current.Import.SetsFile,
+		CardsFile:   settings.Current.Import.CardsFile,
+		RulingsFile: settings.Current.Import.RulingsFile,
+	}
+}
+
+// importLanguages returns the printing languages kept in the catalog. English is
+// always included because it provides the default version of every card.
+func importLanguages() []string {
+	languages := []string{"en"}
+	for _, lang := range settings.Current.Import.Languages {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang != "" && !slices.Contains(languages, lang) {
+			languages = append(languages, lang)
+		}
+	}
+	return languages
+}
+
+// cardsBulkType returns the Scryfall bulk dataset to download: the one set in
+// scryfall.bulkType, or else "default_cards". That dataset only holds one
+// printing per card in its printed language, so localized printings require the
+// much larger "all_cards" dataset.
+func cardsBulkType() string {
+	if bulkType := settings.Current.Scryfall.BulkType; bulkType != "" {
+		return bulkType
+	}
+	if len(importLanguages()) > 1 {
+		return "all_cards"
+	}
+	return "default_cards"
+}
+
+// ResolveImportFile maps a file name passed to reimportMTGData to a path inside
+// import.importDir. Absolute paths and names that leave the directory, also
+// through symlinks, are rejected so API clients cannot read arbitrary files.
+func ResolveImportFile(name string) (string, error) {
+	dir := settings.Current.Import.ImportDir
+	if dir == "" {
+		return "", errors.New("local import files are disabled, set import.importDir to allow them")
+	}
+	if filepath.IsAbs(name) || slices.Contains(strings.Split(filepath.ToSlash(name), "/"), "..") {
+		return "", fmt.Errorf("import file %s must be a relative name inside the import directory", name)
+	}
+
+	base, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(base, name)
+	if !strings.HasPrefix(path, base+string(filepath.Separator)) {
+		return "", fmt.Errorf("import file %s is outside the import directory", name)
+	}
+
+	// Symlinks inside the directory must not point out of it either.
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		resolvedBase, err := filepath.EvalSymlinks(base)
+		if err != nil {
+			return "", err
+		}
+		if !strings.HasPrefix(resolved, resolvedBase+string(filepath.Separator)) {
+			return "", fmt.Errorf("import file %s is outside the import directory", name)
+		}
+	}
+	return path, nil
+}
+
+// Validate checks that the configured local files exist and are regular files.
+func (s ImportSource) Validate() error {
+	for _, path := range []string{s.SetsFile, s.CardsFile, s.RulingsFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("import file %s is not accessible: %w", path, err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("import file %s is a directory", path)
+		}
+	}
+	return nil
+}
+
+// readLocalSetsFile reads a sets snapshot from disk. Both a plain JSON array of
+// sets and a saved Scryfall /sets response (with a "data" array) are accepted.
+func readLocalSetsFile(path string) ([]json.RawMessage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var sets []json.RawMessage
+	if err := json.Unmarshal(data, &sets); err == nil {
+		return sets, nil
+	}
+
+	var response ScryfallResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("sets file %s is neither a JSON array nor a Scryfall list: %w", path, err)
+	}
+	return response.Data, nil
+}
@@ -0,0 +1,89 @@
+package daemons
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+	"magic-helper/graph/model/scryfall"
+
+	"github.com/rs/zerolog/log"
+)
+
+// recordLegalityChanges compares the legalities of each rebuilt card's default
+// version with the snapshot taken before the rebuild (matched to its current
+// key by rekeySnapshots, so re-keyed cards keep their history) and stores one
+// mtg_legality_changes event per card and format whose legality changed. Cards
+// new to the catalog have no earlier legality and record nothing, and neither
+// does a first import into an empty catalog.
+func recordLegalityChanges(ctx context.Context, previous map[string]catalogSnapshot, cards []scryfall.MTG_CardDB) (int, error) {
+	if len(previous) == 0 {
+		log.Info().Msg("No previous catalog, skipping legality history")
+		return 0, nil
+	}
+
+	changedAt := time.Now().UTC().Format(time.RFC3339)
+	events := make([]model.MTGLegalityChangeDB, 0)
+	for _, card := range cards {
+		old, exists := previous[card.ID]
+		if !exists {
+			continue
+		}
+		var legalities map[string]string
+		if v := defaultVersion(card); v != nil {
+			legalities = v.Legalities
+		}
+
+		for _, format := range legalityFormats(old.Legalities, legalities) {
+			before, hadBefore := old.Legalities[format]
+			after, hasAfter := legalities[format]
+			if before == after && hadBefore == hasAfter {
+				continue
+			}
+			event := model.MTGLegalityChangeDB{
+				CardID:    card.ID,
+				Name:      card.Name,
+				Format:    format,
+				ChangedAt: changedAt,
+			}
+			if hadBefore {
+				event.Before = &before
+			}
+			if hasAfter {
+				event.After = &after
+			}
+			events = append(events, event)
+		}
+	}
+
+	for start := 0; start < len(events); start += cardSyncBatchSize {
+		batch := events[start:min(start+cardSyncBatchSize, len(events))]
+		aq := arango.NewQuery( /* aql */ `
+			FOR event IN @events
+				INSERT event INTO mtg_legality_changes
+		`)
+		aq.AddBindVar("events", batch)
+		if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), nil
+}
+
+// legalityFormats returns the formats listed in either legality map, sorted.
+func legalityFormats(a, b map[string]string) []string {
+	formats := make([]string, 0, len(a))
+	for format := range a {
+		formats = append(formats, format)
+	}
+	for format := range b {
+		if _, ok := a[format]; !ok {
+			formats = append(formats, format)
+		}
+	}
+	sort.Strings(formats)
+	return formats
+}
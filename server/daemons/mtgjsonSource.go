@@ -0,0 +1,780 @@
+package daemons
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"magic-helper/graph/model"
+	"magic-helper/graph/model/scryfall"
+	scryfallModel "magic-helper/graph/model/scryfall/model"
+	"magic-helper/settings"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// mtgjsonFetchRecord is the application_config record of MTGJSON imports. Its
+// bulk_updated_at holds the MTGJSON version that was imported last.
+const mtgjsonFetchRecord = "MTGJSON_AllPrintings"
+
+// mtgjsonDataset is the MTGJSON file the source imports. AtomicCards is not used:
+// it has no printings, and the catalog is built from printings.
+const mtgjsonDataset = "AllPrintings"
+
+// mtgjsonSource imports MTGJSON's AllPrintings file, which holds every set with
+// its cards and tokens. Printings are translated into Scryfall's card shape,
+// keyed by their Scryfall IDs, and grouped with the same buildCardGroup as
+// Scryfall imports, so card keys, versions and default printings agree between
+// the two sources. FetchSets streams the file one set at a time into
+// mtg_original_cards, like the Scryfall bulk file, so memory use does not depend
+// on its size; FetchCards then groups the originals with collectCards. Only the
+// sets, the token creators and the rulings are kept in memory between the two.
+type mtgjsonSource struct {
+	sets    []scryfall.MTG_SetDB
+	tokens  []mtgjsonToken
+	rulings map[string][]*model.MtgRuling
+	loaded  bool
+	state   model.MTGApplicationConfig
+	version string
+}
+
+// mtgjsonToken is a token printing together with the names of the cards that
+// create it.
+type mtgjsonToken struct {
+	id        string
+	name      string
+	typeLine  string
+	createdBy []string
+}
+
+// mtgjsonMeta is the MTGJSON Meta.json file.
+type mtgjsonMeta struct {
+	Data struct {
+		Date    string `json:"date"`
+		Version string `json:"version"`
+	} `json:"data"`
+}
+
+// mtgjsonSet is a set of the AllPrintings file.
+type mtgjsonSet struct {
+	Code             string        `json:"code"`
+	Name             string        `json:"name"`
+	Type             string        `json:"type"`
+	ReleaseDate      string        `json:"releaseDate"`
+	Block            *string       `json:"block"`
+	ParentCode       *string       `json:"parentCode"`
+	KeyruneCode      string        `json:"keyruneCode"`
+	MtgoCode         *string       `json:"mtgoCode"`
+	TcgplayerGroupID *int          `json:"tcgplayerGroupId"`
+	BaseSetSize      int           `json:"baseSetSize"`
+	TotalSetSize     int           `json:"totalSetSize"`
+	IsOnlineOnly     bool          `json:"isOnlineOnly"`
+	IsFoilOnly       bool          `json:"isFoilOnly"`
+	IsNonFoilOnly    bool          `json:"isNonFoilOnly"`
+	Cards            []mtgjsonCard `json:"cards"`
+	Tokens           []mtgjsonCard `json:"tokens"`
+}
+
+// mtgjsonCard is a card or token of an AllPrintings set. Multi-faced printings
+// are listed once per face, all sharing the printing's Scryfall ID.
+type mtgjsonCard struct {
+	Name           string             `json:"name"`
+	FaceName       *string            `json:"faceName"`
+	Side           *string            `json:"side"`
+	Layout         string             `json:"layout"`
+	ManaCost       *string            `json:"manaCost"`
+	ManaValue      float64            `json:"manaValue"`
+	Colors         []string           `json:"colors"`
+	ColorIdentity  []string           `json:"colorIdentity"`
+	ColorIndicator *[]string          `json:"colorIndicator"`
+	Type           string             `json:"type"`
+	Text           *string            `json:"text"`
+	FlavorText     *string            `json:"flavorText"`
+	FlavorName     *string            `json:"flavorName"`
+	Power          *string            `json:"power"`
+	Toughness      *string            `json:"toughness"`
+	Loyalty        *string            `json:"loyalty"`
+	Defense        *string            `json:"defense"`
+	Keywords       []string           `json:"keywords"`
+	Legalities     map[string]string  `json:"legalities"`
+	Availability   []string           `json:"availability"`
+	BoosterTypes   []string           `json:"boosterTypes"`
+	Finishes       []string           `json:"finishes"`
+	FrameEffects   []string           `json:"frameEffects"`
+	FrameVersion   string             `json:"frameVersion"`
+	BorderColor    string             `json:"borderColor"`
+	Artist         *string            `json:"artist"`
+	Rarity         string             `json:"rarity"`
+	Number         string             `json:"number"`
+	Language       string             `json:"language"`
+	Watermark      *string            `json:"watermark"`
+	SecurityStamp  *string            `json:"securityStamp"`
+	PromoTypes     []string           `json:"promoTypes"`
+	EdhrecRank     *int               `json:"edhrecRank"`
+	IsAlternative  bool               `json:"isAlternative"`
+	IsFullArt      bool               `json:"isFullArt"`
+	IsGameChanger  bool               `json:"isGameChanger"`
+	IsOnlineOnly   bool               `json:"isOnlineOnly"`
+	IsOversized    bool               `json:"isOversized"`
+	IsPromo        bool               `json:"isPromo"`
+	IsReprint      bool               `json:"isReprint"`
+	IsReserved     bool               `json:"isReserved"`
+	IsTextless     bool               `json:"isTextless"`
+	SetCode        string             `json:"setCode"`
+	Identifiers    mtgjsonIdentifiers `json:"identifiers"`
+	Rulings        []struct {
+		Date string `json:"date"`
+		Text string `json:"text"`
+	} `json:"rulings"`
+	RelatedCards *struct {
+		ReverseRelated []string `json:"reverseRelated"`
+	} `json:"relatedCards"`
+}
+
+// mtgjsonIdentifiers holds the external IDs of an MTGJSON card. MTGJSON stores
+// numeric IDs as strings.
+type mtgjsonIdentifiers struct {
+	ScryfallID             string  `json:"scryfallId"`
+	ScryfallOracleID       string  `json:"scryfallOracleId"`
+	ScryfallIllustrationID *string `json:"scryfallIllustrationId"`
+	MtgArenaID             string  `json:"mtgArenaId"`
+	MtgoID                 string  `json:"mtgoId"`
+	TcgplayerProductID     string  `json:"tcgplayerProductId"`
+	McmID                  string  `json:"mcmId"`
+}
+
+// mtgjsonFormats are the formats Scryfall lists on every card. MTGJSON leaves out
+// the formats a card is not legal in; they are filled in as not_legal so the
+// legality history does not change when the source is switched.
+var mtgjsonFormats = []string{
+	"alchemy", "brawl", "commander", "duel", "future", "gladiator", "historic", "legacy", "modern",
+	"oathbreaker", "oldschool", "pauper", "paupercommander", "penny", "pioneer", "predh", "premodern",
+	"standard", "standardbrawl", "timeless", "vintage",
+}
+
+// mtgjsonLanguages maps MTGJSON language names to Scryfall language codes.
+var mtgjsonLanguages = map[string]string{
+	"English":             "en",
+	"Spanish":             "es",
+	"French":              "fr",
+	"German":              "de",
+	"Italian":             "it",
+	"Portuguese (Brazil)": "pt",
+	"Japanese":            "ja",
+	"Korean":              "ko",
+	"Russian":             "ru",
+	"Chinese Simplified":  "zhs",
+	"Chinese Traditional": "zht",
+	"Hebrew":              "he",
+	"Latin":               "la",
+	"Ancient Greek":       "grc",
+	"Arabic":              "ar",
+	"Sanskrit":            "sa",
+	"Phyrexian":           "ph",
+}
+
+// mtgjsonExcludedSetTypes are the set types left out of the card catalog, as in
+// the Scryfall import.
+var mtgjsonExcludedSetTypes = []string{"promo", "funny", "memorabilia", "vanguard", "token"}
+
+// Name implements CardSource.
+func (s *mtgjsonSource) Name() string {
+	return "MTGJSON"
+}
+
+// FetchStates implements CardSource.
+func (s *mtgjsonSource) FetchStates() []string {
+	return []string{mtgjsonFetchRecord}
+}
+
+// ProvidesPrices implements CardSource. AllPrintings has no prices, and AllPrices
+// is not imported.
+func (s *mtgjsonSource) ProvidesPrices() bool {
+	return false
+}
+
+// ProvidesRulings implements CardSource. Rulings come with the printings.
+func (s *mtgjsonSource) ProvidesRulings() bool {
+	return true
+}
+
+// FetchSets implements CardSource. It reads the local AllPrintings file set in
+// source.CardsFile, or downloads AllPrintings when MTGJSON published a version
+// other than the one imported last, and stores its printings as originals.
+// Only a downloaded file prunes the originals it no longer lists.
+func (s *mtgjsonSource) FetchSets(ctx context.Context, source ImportSource, m *ImportManager) (SetCatalog, bool, error) {
+	path := source.CardsFile
+	if path != "" {
+		log.Info().Msgf("Importing MTGJSON %s from local file %s", mtgjsonDataset, path)
+	} else {
+		var changed bool
+		var err error
+		path, changed, err = s.download(ctx, m)
+		if err != nil || !changed {
+			return SetCatalog{}, false, err
+		}
+	}
+
+	if err := s.readFile(ctx, path, source.CardsFile == "", m); err != nil {
+		log.Error().Err(err).Msgf("Error reading MTGJSON file %s", path)
+		return SetCatalog{}, false, err
+	}
+	s.loaded = true
+
+	m.reportSets(len(s.sets))
+	log.Info().Int("sets", len(s.sets)).Int("rulings", len(s.rulings)).Msg("Read MTGJSON printings")
+	return SetCatalog{Sets: s.sets}, true, nil
+}
+
+// FetchCards implements CardSource. It groups the originals stored by FetchSets,
+// stores the rulings unless a local rulings file replaces them, and returns the
+// imported version as the fetch state to save once the catalog is stored.
+func (s *mtgjsonSource) FetchCards(ctx context.Context, source ImportSource, m *ImportManager) (CardCatalog, bool, error) {
+	if !s.loaded {
+		return CardCatalog{}, false, nil
+	}
+	if m != nil {
+		m.setPhase(PhaseProcessingCards, "Processing and grouping cards...", bandUpsertEnd)
+	}
+
+	catalog, err := collectCards(ctx, m)
+	if err != nil {
+		return CardCatalog{}, false, err
+	}
+	s.relateTokens(catalog)
+
+	if source.RulingsFile == "" {
+		if err := s.storeRulings(ctx); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return CardCatalog{}, false, err
+			}
+			log.Warn().Err(err).Msg("Error storing MTGJSON rulings, keeping the stored ones")
+		}
+	}
+
+	// Local files don't count as a fetch, so the next download isn't skipped.
+	if source.CardsFile == "" {
+		s.state.BulkType = mtgjsonDataset
+		s.state.BulkUpdatedAt = s.version
+		catalog.FetchStates = append(catalog.FetchStates, s.state)
+	}
+	return catalog, true, nil
+}
+
+// download checks MTGJSON's Meta.json and, when its version differs from the one
+// imported last, downloads AllPrintings.json. It returns false without an error
+// when the version is unchanged.
+func (s *mtgjsonSource) download(ctx context.Context, m *ImportManager) (string, bool, error) {
+	var err error
+	s.state, err = readFetchState(mtgjsonFetchRecord)
+	if err != nil {
+		log.Error().Err(err).Msg("Error reading MTGJSON fetch state")
+		return "", false, err
+	}
+
+	baseURL := settings.Current.MTGJSON.BaseURL
+	body, err := fetchBodyWithContext(ctx, baseURL+"/Meta.json")
+	if err != nil {
+		log.Error().Err(err).Msg("Error fetching MTGJSON meta")
+		return "", false, err
+	}
+	var meta mtgjsonMeta
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return "", false, fmt.Errorf("decoding MTGJSON meta: %w", err)
+	}
+	if meta.Data.Version == "" {
+		return "", false, errors.New("MTGJSON meta has no version")
+	}
+	s.version = meta.Data.Version
+
+	if s.state.BulkType == mtgjsonDataset && s.state.BulkUpdatedAt == s.version {
+		log.Info().Str("version", s.version).Msgf("MTGJSON %s unchanged since last fetch, skipping", mtgjsonDataset)
+		return "", false, nil
+	}
+
+	// AllPrintings is republished under the same URL, so a completed download of an
+	// older version must not be reused.
+	filename := mtgjsonDataset + ".json"
+	if err := os.Remove(filepath.Join(bulkDownloadDir, filename)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", false, err
+	}
+
+	downloadURL := baseURL + "/" + filename
+	log.Info().Str("version", s.version).Msgf("Found MTGJSON %s. Fetching from: %s", mtgjsonDataset, downloadURL)
+	var path string
+	err = retryWithBackoff(ctx, "download "+downloadURL, func() error {
+		var err error
+		path, err = downloadBulkFile(ctx, downloadURL, filename, 0, m.reportDownload)
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Msgf("Error downloading MTGJSON file from %s", downloadURL)
+		return "", false, err
+	}
+	return path, true, nil
+}
+
+// readFile decodes an AllPrintings file one set at a time, keeps its sets and
+// stores the printings of each set before decoding the next. With prune set,
+// the originals the file did not list are pruned afterwards.
+func (s *mtgjsonSource) readFile(ctx context.Context, path string, prune bool, m *ImportManager) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var fileSize int64
+	if info, err := file.Stat(); err == nil {
+		fileSize = info.Size()
+	}
+	counter := &countingReader{reader: file}
+	decoder := json.NewDecoder(bufio.NewReaderSize(counter, 1024*1024))
+
+	runID := m.runID()
+	stored := 0
+	s.rulings = make(map[string][]*model.MtgRuling)
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if key != "data" {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := expectDelim(decoder, '{'); err != nil {
+			return err
+		}
+		for decoder.More() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			code, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			// A set that is valid JSON but not the expected shape is quarantined
+			// and the rest of the file is still read.
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				return fmt.Errorf("decoding MTGJSON set %v: %w", code, err)
+			}
+			var set mtgjsonSet
+			err = json.Unmarshal(raw, &set)
+			if err == nil && (set.Code == "" || set.Name == "") {
+				err = errors.New("set has no code or name")
+			}
+			if err != nil {
+				record := quarantineRecord(model.MtgQuarantineKindMtgjsonSet, fmt.Sprint(code), raw, err, runID)
+				if err := storeQuarantined(ctx, []model.MTGQuarantinedRecordDB{record}, m); err != nil {
+					return err
+				}
+				continue
+			}
+			n, err := s.addSet(ctx, set, runID, m)
+			if err != nil {
+				return err
+			}
+			stored += n
+			m.reportCardBatch(n, counter.read, fileSize)
+		}
+		if err := expectDelim(decoder, '}'); err != nil {
+			return err
+		}
+	}
+
+	if !prune {
+		log.Info().Msg("Cards read from a local file, skipping original card pruning")
+		return nil
+	}
+	return pruneUnseenOriginals(ctx, stored, runID, m)
+}
+
+// expectDelim reads the next token and fails unless it is delim.
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %v in MTGJSON file, got %v", delim, token)
+	}
+	return nil
+}
+
+// addSet keeps a set, the creators of its tokens and the rulings of its cards,
+// and stores its printings in mtg_original_cards. It returns the number of
+// printings stored.
+func (s *mtgjsonSource) addSet(ctx context.Context, set mtgjsonSet, runID string, m *ImportManager) (int, error) {
+	code := strings.ToLower(set.Code)
+	dbSet := scryfall.MTG_SetDB{
+		ID:          code,
+		Code:        code,
+		MTGOCode:    lowerPtr(set.MtgoCode),
+		TCGPlayerID: set.TcgplayerGroupID,
+		Name:        set.Name,
+		SetType:     set.Type,
+		Block:       set.Block,
+		CardCount:   set.TotalSetSize,
+		Digital:     set.IsOnlineOnly,
+		FoilOnly:    set.IsFoilOnly,
+		NonFoilOnly: set.IsNonFoilOnly,
+	}
+	if set.ReleaseDate != "" {
+		dbSet.ReleasedAt = &set.ReleaseDate
+	}
+	if set.ParentCode != nil {
+		dbSet.ParentSetCode = lowerPtr(set.ParentCode)
+	}
+	if set.BaseSetSize > 0 {
+		dbSet.PrintedSize = &set.BaseSetSize
+	}
+	if set.KeyruneCode != "" {
+		dbSet.IconSVGURI = "https://svgs.scryfall.io/sets/" + strings.ToLower(set.KeyruneCode) + ".svg"
+	}
+	s.sets = append(s.sets, dbSet)
+	s.addRulings(set.Cards)
+
+	printings := mtgjsonPrintings(set, set.Cards)
+
+	// The creating cards are listed on every face; the first face is enough.
+	createdBy := make(map[string][]string)
+	for _, entry := range set.Tokens {
+		id := entry.Identifiers.ScryfallID
+		if entry.RelatedCards != nil && createdBy[id] == nil {
+			createdBy[id] = entry.RelatedCards.ReverseRelated
+		}
+	}
+	for _, token := range mtgjsonPrintings(set, set.Tokens) {
+		// Scryfall lists tokens in token sets, which the card catalog leaves out.
+		token.SetType = "token"
+		printings = append(printings, token)
+		if len(createdBy[token.ID]) > 0 {
+			s.tokens = append(s.tokens, mtgjsonToken{id: token.ID, name: token.Name, typeLine: token.TypeLine, createdBy: createdBy[token.ID]})
+		}
+	}
+
+	raws := make([]json.RawMessage, 0, len(printings))
+	for _, printing := range printings {
+		raw, err := json.Marshal(printing)
+		if err != nil {
+			return 0, err
+		}
+		raws = append(raws, raw)
+	}
+	return storeOriginalBatch(ctx, raws, runID, m)
+}
+
+// addRulings keeps the rulings of entries by oracle ID. Reprints carry the same
+// rulings, so the first printing seen is enough.
+func (s *mtgjsonSource) addRulings(entries []mtgjsonCard) {
+	for _, entry := range entries {
+		oracleID := entry.Identifiers.ScryfallOracleID
+		if oracleID == "" || len(entry.Rulings) == 0 {
+			continue
+		}
+		if _, seen := s.rulings[oracleID]; seen {
+			continue
+		}
+		rulings := make([]*model.MtgRuling, 0, len(entry.Rulings))
+		for _, ruling := range entry.Rulings {
+			if ruling.Text == "" {
+				continue
+			}
+			rulings = append(rulings, &model.MtgRuling{Source: "wotc", PublishedAt: ruling.Date, Comment: ruling.Text})
+		}
+		sort.SliceStable(rulings, func(i, j int) bool { return rulings[i].PublishedAt < rulings[j].PublishedAt })
+		s.rulings[oracleID] = rulings
+	}
+}
+
+// storeRulings replaces mtg_card_rulings with the rulings read from the file. A
+// file without any rulings keeps the stored ones.
+func (s *mtgjsonSource) storeRulings(ctx context.Context) error {
+	if len(s.rulings) == 0 {
+		log.Warn().Msg("MTGJSON file holds no rulings, keeping the stored ones")
+		return nil
+	}
+	documents := make([]model.MTGCardRulingsDB, 0, len(s.rulings))
+	for oracleID, rulings := range s.rulings {
+		documents = append(documents, model.MTGCardRulingsDB{ID: oracleID, Rulings: rulings})
+	}
+	if err := storeRulings(ctx, documents); err != nil {
+		return err
+	}
+	log.Info().Int("cards", len(documents)).Msg("Stored MTGJSON rulings")
+	return nil
+}
+
+// mtgjsonPrintings merges the per-face entries of a set into one Scryfall-shaped
+// card per Scryfall ID. Entries without a Scryfall ID are skipped, since the
+// catalog keys versions by it.
+func mtgjsonPrintings(set mtgjsonSet, entries []mtgjsonCard) []scryfall.Card {
+	var order []string
+	faces := make(map[string][]mtgjsonCard)
+	for _, entry := range entries {
+		id := entry.Identifiers.ScryfallID
+		if id == "" {
+			continue
+		}
+		if _, ok := faces[id]; !ok {
+			order = append(order, id)
+		}
+		faces[id] = append(faces[id], entry)
+	}
+
+	cards := make([]scryfall.Card, 0, len(order))
+	for _, id := range order {
+		parts := faces[id]
+		sort.SliceStable(parts, func(i, j int) bool {
+			return safeString(parts[i].Side) < safeString(parts[j].Side)
+		})
+		cards = append(cards, mtgjsonToCard(set, id, parts))
+	}
+	return cards
+}
+
+// mtgjsonToCard translates the face entries of one printing into a Scryfall card.
+func mtgjsonToCard(set mtgjsonSet, id string, parts []mtgjsonCard) scryfall.Card {
+	first := parts[0]
+	card := scryfall.Card{
+		ID:              id,
+		Lang:            scryfallModel.CardLanguage(mtgjsonLanguage(first.Language)),
+		Layout:          scryfallModel.Layout(first.Layout),
+		Name:            first.Name,
+		CMC:             first.ManaValue,
+		ColorIdentity:   first.ColorIdentity,
+		EDHRecRank:      first.EdhrecRank,
+		GameChanger:     &first.IsGameChanger,
+		Keywords:        first.Keywords,
+		Legalities:      mtgjsonLegalities(first.Legalities),
+		Reserved:        first.IsReserved,
+		Artist:          first.Artist,
+		Booster:         len(first.BoosterTypes) > 0,
+		BorderColor:     scryfallModel.BorderColor(first.BorderColor),
+		CollectorNumber: first.Number,
+		Digital:         first.IsOnlineOnly,
+		Finishes:        first.Finishes,
+		FlavorName:      first.FlavorName,
+		Frame:           first.FrameVersion,
+		FullArt:         first.IsFullArt,
+		Games:           mtgjsonGames(first.Availability),
+		IllustrationID:  first.Identifiers.ScryfallIllustrationID,
+		Oversized:       first.IsOversized,
+		Promo:           first.IsPromo,
+		Rarity:          scryfallModel.Rarity(first.Rarity),
+		ReleasedAt:      set.ReleaseDate,
+		Reprint:         first.IsReprint,
+		SetName:         set.Name,
+		SetType:         set.Type,
+		Set:             strings.ToLower(cmp.Or(first.SetCode, set.Code)),
+		Textless:        first.IsTextless,
+		Variation:       first.IsAlternative,
+		SecurityStamp:   first.SecurityStamp,
+		Watermark:       first.Watermark,
+		ArenaID:         atoiPtr(first.Identifiers.MtgArenaID),
+		MtgoID:          atoiPtr(first.Identifiers.MtgoID),
+		TcgplayerID:     atoiPtr(first.Identifiers.TcgplayerProductID),
+		CardmarketID:    atoiPtr(first.Identifiers.McmID),
+	}
+	if len(first.FrameEffects) > 0 {
+		card.FrameEffects = &first.FrameEffects
+	}
+	if len(first.PromoTypes) > 0 {
+		card.PromoTypes = &first.PromoTypes
+	}
+
+	// Reversible cards and double-faced tokens carry an oracle ID per face.
+	faceOracleIDs := first.Layout == "reversible_card" || first.Layout == "double_faced_token"
+	if oracleID := first.Identifiers.ScryfallOracleID; oracleID != "" && !faceOracleIDs {
+		card.OracleID = &oracleID
+	}
+
+	if len(parts) == 1 && first.Side == nil {
+		colors := first.Colors
+		card.Colors = &colors
+		card.ColorIndicator = first.ColorIndicator
+		card.ManaCost = first.ManaCost
+		card.OracleText = first.Text
+		card.TypeLine = first.Type
+		card.Power = first.Power
+		card.Toughness = first.Toughness
+		card.Loyalty = first.Loyalty
+		card.Defense = first.Defense
+		card.FlavorText = first.FlavorText
+		card.ImageUris = scryfallImageURIs(id, "front")
+		return card
+	}
+
+	// Double-sided printings have an image per face, the others share one.
+	doubleSided := slices.Contains([]string{"transform", "modal_dfc", "reversible_card", "double_faced_token"}, first.Layout)
+	faces := make([]scryfall.CardFace, 0, len(parts))
+	typeLines := make([]string, 0, len(parts))
+	costs := make([]string, 0, len(parts))
+	var colors []string
+	for i, part := range parts {
+		typeLine := part.Type
+		face := scryfall.CardFace{
+			Artist:         part.Artist,
+			ColorIndicator: part.ColorIndicator,
+			Defense:        part.Defense,
+			FlavorText:     part.FlavorText,
+			Loyalty:        part.Loyalty,
+			ManaCost:       safeString(part.ManaCost),
+			Name:           safeString(part.FaceName),
+			OracleText:     part.Text,
+			Power:          part.Power,
+			Toughness:      part.Toughness,
+			TypeLine:       &typeLine,
+			Watermark:      part.Watermark,
+		}
+		if face.Name == "" {
+			face.Name = part.Name
+		}
+		if len(part.Colors) > 0 {
+			faceColors := part.Colors
+			face.Colors = &faceColors
+		}
+		if faceOracleIDs && part.Identifiers.ScryfallOracleID != "" {
+			oracleID := part.Identifiers.ScryfallOracleID
+			face.OracleID = &oracleID
+		}
+		if doubleSided {
+			side := "front"
+			if i > 0 {
+				side = "back"
+			}
+			face.ImageUris = scryfallImageURIs(id, side)
+		}
+		faces = append(faces, face)
+		typeLines = append(typeLines, part.Type)
+		costs = append(costs, face.ManaCost)
+		for _, color := range part.Colors {
+			if !slices.Contains(colors, color) {
+				colors = append(colors, color)
+			}
+		}
+	}
+	card.CardFaces = &faces
+	card.TypeLine = strings.Join(typeLines, " // ")
+	if !doubleSided {
+		manaCost := strings.Join(costs, " // ")
+		card.ManaCost = &manaCost
+		card.Colors = &colors
+		card.ImageUris = scryfallImageURIs(id, "front")
+	}
+	return card
+}
+
+// relateTokens adds the token relations of the cards MTGJSON names as token
+// creators to catalog. MTGJSON does not list meld or combo relations, so only
+// token relations are kept.
+func (s *mtgjsonSource) relateTokens(catalog CardCatalog) {
+	keyByName := make(map[string]string, len(catalog.Cards))
+	for _, card := range catalog.Cards {
+		keyByName[strings.ToLower(card.Name)] = card.ID
+	}
+	related := 0
+	for _, token := range s.tokens {
+		for _, name := range token.createdBy {
+			key, ok := keyByName[strings.ToLower(name)]
+			if !ok {
+				continue
+			}
+			catalog.RelatedParts[key] = append(catalog.RelatedParts[key], scryfall.RelatedCard{
+				ID:        token.id,
+				Component: scryfallModel.RelatedCardComponentToken,
+				Name:      token.name,
+				TypeLine:  token.typeLine,
+			})
+			related++
+		}
+	}
+	log.Info().Int("relations", related).Msg("Related MTGJSON tokens to their creators")
+}
+
+// mtgjsonLegalities converts MTGJSON legalities ("Legal", "Not Legal", ...) to
+// Scryfall's, listing the formats MTGJSON leaves out as not_legal.
+func mtgjsonLegalities(legalities map[string]string) map[string]string {
+	result := make(map[string]string, len(mtgjsonFormats))
+	for _, format := range mtgjsonFormats {
+		result[format] = "not_legal"
+	}
+	for format, legality := range legalities {
+		result[strings.ToLower(format)] = strings.ReplaceAll(strings.ToLower(legality), " ", "_")
+	}
+	return result
+}
+
+// mtgjsonLanguage returns the Scryfall code of an MTGJSON language name.
+func mtgjsonLanguage(language string) string {
+	if code, ok := mtgjsonLanguages[language]; ok {
+		return code
+	}
+	if language == "" {
+		return "en"
+	}
+	return strings.ToLower(language)
+}
+
+// mtgjsonGames converts MTGJSON availability to Scryfall games, which use the
+// same names.
+func mtgjsonGames(availability []string) []scryfallModel.Game {
+	games := make([]scryfallModel.Game, 0, len(availability))
+	for _, game := range availability {
+		games = append(games, scryfallModel.Game(game))
+	}
+	return games
+}
+
+// scryfallImageURIs returns the Scryfall CDN images of one side ("front" or
+// "back") of a printing, which MTGJSON does not list.
+func scryfallImageURIs(id string, side string) *scryfall.ImageUris {
+	if len(id) < 2 {
+		return nil
+	}
+	path := side + "/" + id[0:1] + "/" + id[1:2] + "/" + id
+	return &scryfall.ImageUris{
+		ArtCrop:    "https://cards.scryfall.io/art_crop/" + path + ".jpg",
+		BorderCrop: "https://cards.scryfall.io/border_crop/" + path + ".jpg",
+		Large:      "https://cards.scryfall.io/large/" + path + ".jpg",
+		Normal:     "https://cards.scryfall.io/normal/" + path + ".jpg",
+		Small:      "https://cards.scryfall.io/small/" + path + ".jpg",
+		PNG:        "https://cards.scryfall.io/png/" + path + ".png",
+	}
+}
+
+// atoiPtr parses an MTGJSON string ID, returning nil when it is empty or not a
+// number.
+func atoiPtr(value string) *int {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// lowerPtr returns a lowercased copy of a set code, or nil.
+func lowerPtr(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	lower := strings.ToLower(*value)
+	return &lower
+}
@@ -0,0 +1,103 @@
+package daemons
+
+import (
+	"context"
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+	"magic-helper/graph/model/scryfall"
+	"magic-helper/settings"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// priceDateLayout formats the day of a price snapshot.
+const priceDateLayout = "2006-01-02"
+
+// convertPrices parses Scryfall's string prices, returning nil when the printing
+// has no price at all.
+func convertPrices(prices scryfall.Prices) *model.MtgPrices {
+	converted := model.MtgPrices{
+		Usd:       parsePrice(prices.USD),
+		UsdFoil:   parsePrice(prices.USDFoil),
+		UsdEtched: parsePrice(prices.USDEtched),
+		Eur:       parsePrice(prices.EUR),
+		EurFoil:   parsePrice(prices.EURFoil),
+		EurEtched: parsePrice(prices.EUREtched),
+		Tix:       parsePrice(prices.Tix),
+	}
+	if converted == (model.MtgPrices{}) {
+		return nil
+	}
+	return &converted
+}
+
+// parsePrice parses one Scryfall price string; missing or malformed prices are nil.
+func parsePrice(price *string) *float64 {
+	if price == nil || *price == "" {
+		return nil
+	}
+	value, err := strconv.ParseFloat(*price, 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
+// recordPriceSnapshot stores today's prices of every version of cards in
+// mtg_card_prices, replacing a snapshot already taken today, and removes
+// snapshots older than the configured history length. The latest snapshot of a
+// version is its current price; mtg_cards does not store prices.
+func recordPriceSnapshot(ctx context.Context, cards []scryfall.MTG_CardDB) error {
+	today := time.Now().UTC()
+	date := today.Format(priceDateLayout)
+	log.Info().Str("date", date).Msg("Recording price snapshot")
+
+	snapshots := make([]model.MTGCardPriceDB, 0)
+	for _, card := range cards {
+		for _, v := range card.Versions {
+			if v.Prices == nil {
+				continue
+			}
+			snapshots = append(snapshots, model.MTGCardPriceDB{
+				ID:        v.ID + "_" + date,
+				VersionID: v.ID,
+				Date:      date,
+				Prices:    *v.Prices,
+			})
+		}
+	}
+
+	for start := 0; start < len(snapshots); start += cardSyncBatchSize {
+		batch := snapshots[start:min(start+cardSyncBatchSize, len(snapshots))]
+		aq := arango.NewQuery( /* aql */ `
+			FOR price IN @prices
+				INSERT price INTO mtg_card_prices
+				OPTIONS { overwriteMode: "replace" }
+		`)
+		aq.AddBindVar("prices", batch)
+
+		if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+			log.Error().Err(err).Msg("Error recording price snapshot")
+			return err
+		}
+	}
+
+	days := settings.Current.Import.PriceHistoryDays
+	cutoff := today.AddDate(0, 0, -days).Format(priceDateLayout)
+	aq := arango.NewQuery( /* aql */ `
+		FOR price IN mtg_card_prices
+			FILTER price.date < @cutoff
+			REMOVE price IN mtg_card_prices
+	`)
+	aq.AddBindVar("cutoff", cutoff)
+
+	if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+		log.Error().Err(err).Msg("Error pruning price history")
+		return err
+	}
+
+	log.Info().Str("date", date).Int("keepDays", days).Msg("Price snapshot recorded")
+	return nil
+}
@@ -0,0 +1,139 @@
+package daemons
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+	"magic-helper/graph/model/scryfall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// quarantineRecord describes a malformed upstream record rejected by kind. raw is
+// the record as received; recordID is its Scryfall ID or set code, or "" when it
+// could not be read. Records are keyed by kind and identity, so a record that is
+// still malformed in a later run replaces its earlier entry.
+func quarantineRecord(kind model.MtgQuarantineKind, recordID string, raw []byte, err error, runID string) model.MTGQuarantinedRecordDB {
+	record := model.MTGQuarantinedRecordDB{
+		RunID:         runID,
+		Kind:          kind,
+		Raw:           string(raw),
+		Error:         err.Error(),
+		QuarantinedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	identity := recordID
+	switch {
+	case recordID != "":
+		record.RecordID = &recordID
+	case len(raw) > 0:
+		identity = string(raw)
+	default:
+		identity = record.Error
+	}
+	sum := sha256.Sum256([]byte(string(kind) + "|" + identity))
+	record.ID = hex.EncodeToString(sum[:16])
+	return record
+}
+
+// storeQuarantined writes records to mtg_quarantine, replacing the entries an
+// earlier run made for the same records, and reports them to m.
+func storeQuarantined(ctx context.Context, records []model.MTGQuarantinedRecordDB, m *ImportManager) error {
+	if len(records) == 0 {
+		return nil
+	}
+	for _, record := range records {
+		log.Warn().Str("kind", string(record.Kind)).Str("record", safeString(record.RecordID)).Str("error", record.Error).Msg("Quarantined malformed record")
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+		FOR record IN @records
+			INSERT record INTO mtg_quarantine
+			OPTIONS { overwriteMode: "replace" }
+	`)
+	aq.AddBindVar("records", records)
+
+	if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+		log.Error().Err(err).Msg("Error storing quarantined records")
+		return err
+	}
+	m.reportQuarantined(len(records))
+	return nil
+}
+
+// keepQuarantinedOriginals tags the stored originals of quarantined cards with
+// runID, so pruning keeps their last good copy instead of dropping the card.
+func keepQuarantinedOriginals(ctx context.Context, records []model.MTGQuarantinedRecordDB, runID string) error {
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		if record.RecordID != nil {
+			ids = append(ids, *record.RecordID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+		FOR c IN mtg_original_cards
+			FILTER c._key IN @ids
+			UPDATE c WITH { importRunID: @runID } IN mtg_original_cards
+	`)
+	aq.AddBindVar("ids", ids)
+	aq.AddBindVar("runID", runID)
+
+	if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+		log.Error().Err(err).Msg("Error keeping originals of quarantined cards")
+		return err
+	}
+	return nil
+}
+
+// probeRecordID reads the Scryfall ID or set code of a record that failed to
+// decode, or returns "" when even that is unreadable.
+func probeRecordID(raw []byte) string {
+	var probe struct {
+		ID   string `json:"id"`
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return ""
+	}
+	if probe.ID != "" {
+		return probe.ID
+	}
+	return probe.Code
+}
+
+// validateSet checks that a decoded Scryfall set has the fields mtg_sets relies on.
+func validateSet(set scryfall.Set) error {
+	if set.Code == "" {
+		return errors.New("set has no code")
+	}
+	if set.Name == "" {
+		return errors.New("set has no name")
+	}
+	return nil
+}
+
+// validateCard checks that a decoded Scryfall card has the fields grouping and
+// the catalog rely on.
+func validateCard(card scryfall.Card) error {
+	switch {
+	case card.ID == "":
+		return errors.New("card has no id")
+	case card.Name == "":
+		return errors.New("card has no name")
+	case card.Lang == "":
+		return errors.New("card has no lang")
+	case card.Set == "":
+		return errors.New("card has no set")
+	case card.Layout == "":
+		return errors.New("card has no layout")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package daemons
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+	"magic-helper/graph/model/scryfall"
+	scryfallModel "magic-helper/graph/model/scryfall/model"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// syncRelatedCards turns the all_parts lists of the curated cards into
+// mtg_card_related_card edges. A part is resolved to the card or token holding
+// the referenced printing, or, for anything but tokens, to the card of that
+// name when the printing itself was not kept. Edges always start at a card in
+// mtg_cards and end at a card or at a token in mtg_tokens; edges that no longer
+// exist are removed.
+func syncRelatedCards(ctx context.Context, cards []scryfall.MTG_CardDB, tokens []scryfall.MTG_CardDB, relatedParts map[string][]scryfall.RelatedCard) error {
+	cardsCollection := arango.MTG_CARDS_COLLECTION.String() + "/"
+	tokensCollection := arango.MTG_TOKENS_COLLECTION.String() + "/"
+
+	idByVersionID := make(map[string]string)
+	keyByName := make(map[string]string)
+	for _, token := range tokens {
+		for _, version := range token.Versions {
+			idByVersionID[version.ID] = tokensCollection + token.ID
+		}
+	}
+	for _, card := range cards {
+		for _, version := range card.Versions {
+			idByVersionID[version.ID] = cardsCollection + card.ID
+		}
+		keyByName[strings.ToLower(card.Name)] = card.ID
+		if front, _, ok := strings.Cut(card.Name, " // "); ok {
+			if _, taken := keyByName[strings.ToLower(front)]; !taken {
+				keyByName[strings.ToLower(front)] = card.ID
+			}
+		}
+	}
+
+	edges := make(map[string]model.MTGCardRelatedCardDB)
+	for from, parts := range relatedParts {
+		for _, part := range parts {
+			to, ok := idByVersionID[part.ID]
+			if !ok && part.Component != scryfallModel.RelatedCardComponentToken {
+				var key string
+				key, ok = keyByName[strings.ToLower(part.Name)]
+				to = cardsCollection + key
+			}
+			if !ok || to == cardsCollection+from {
+				continue
+			}
+			edge := model.MTGCardRelatedCardDB{
+				From:      cardsCollection + from,
+				To:        to,
+				Component: string(part.Component),
+			}
+			edge.ID = relatedCardEdgeKey(edge)
+			edges[edge.ID] = edge
+		}
+	}
+
+	stored, err := loadRelatedCardEdgeKeys(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Error loading related card edges")
+		return err
+	}
+
+	var inserts []model.MTGCardRelatedCardDB
+	for key, edge := range edges {
+		if !stored[key] {
+			inserts = append(inserts, edge)
+		}
+	}
+	var removals []string
+	for key := range stored {
+		if _, ok := edges[key]; !ok {
+			removals = append(removals, key)
+		}
+	}
+	sort.Slice(inserts, func(i, j int) bool { return inserts[i].ID < inserts[j].ID })
+
+	for start := 0; start < len(inserts); start += cardSyncBatchSize {
+		aq := arango.NewQuery( /* aql */ `
+			FOR e IN @edges
+				INSERT e IN mtg_card_related_card
+		`)
+		aq.AddBindVar("edges", inserts[start:min(start+cardSyncBatchSize, len(inserts))])
+		if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+			log.Error().Err(err).Msg("Error inserting related card edges")
+			return err
+		}
+	}
+
+	for start := 0; start < len(removals); start += cardSyncBatchSize {
+		aq := arango.NewQuery( /* aql */ `
+			FOR key IN @keys
+				REMOVE key IN mtg_card_related_card
+		`)
+		aq.AddBindVar("keys", removals[start:min(start+cardSyncBatchSize, len(removals))])
+		if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+			log.Error().Err(err).Msg("Error removing related card edges")
+			return err
+		}
+	}
+
+	log.Info().Int("edges", len(edges)).Int("inserted", len(inserts)).Int("removed", len(removals)).Msg("Related cards synced")
+	return nil
+}
+
+// relatedCardEdgeKey derives a stable key from an edge's endpoints and component,
+// so an unchanged relation keeps its document across imports.
+func relatedCardEdgeKey(edge model.MTGCardRelatedCardDB) string {
+	sum := sha256.Sum256([]byte(edge.From + "|" + edge.To + "|" + edge.Component))
+	return hex.EncodeToString(sum[:16])
+}
+
+// loadRelatedCardEdgeKeys returns the keys of all stored related card edges.
+func loadRelatedCardEdgeKeys(ctx context.Context) (map[string]bool, error) {
+	aq := arango.NewQuery( /* aql */ `
+		FOR e IN mtg_card_related_card
+			RETURN e._key
+	`)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	keys := make(map[string]bool)
+	for cursor.HasMore() {
+		var key string
+		if _, err := cursor.ReadDocument(ctx, &key); err != nil {
+			return nil, err
+		}
+		keys[key] = true
+	}
+	return keys, nil
+}
@@ -0,0 +1,99 @@
+package daemons
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// retryAttempts is how often a transient request failure is tried in total.
+	retryAttempts = 5
+	// retryBaseDelay is the wait before the first retry; it doubles per attempt.
+	retryBaseDelay = 2 * time.Second
+	// retryMaxDelay caps the wait between two attempts.
+	retryMaxDelay = time.Minute
+)
+
+// HTTPStatusError reports a response whose status code was not the expected one.
+type HTTPStatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration // from the Retry-After header, 0 when absent
+}
+
+// Error implements the error interface.
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected status %s from %s", e.Status, e.URL)
+}
+
+// newHTTPStatusError builds an HTTPStatusError from resp.
+func newHTTPStatusError(url string, resp *http.Response) *HTTPStatusError {
+	err := &HTTPStatusError{URL: url, StatusCode: resp.StatusCode, Status: resp.Status}
+	if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+		err.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return err
+}
+
+// isTransient reports whether err is worth retrying: network failures, truncated
+// bodies, rate limiting and server errors. Cancellation is never retried.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// retryWithBackoff calls fn until it succeeds, fails with an error that is not
+// transient, or retryAttempts is reached. The wait between attempts doubles from
+// retryBaseDelay up to retryMaxDelay, or follows the server's Retry-After.
+func retryWithBackoff(ctx context.Context, operation string, fn func() error) error {
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if !isTransient(err) {
+			return err
+		}
+		if attempt >= retryAttempts {
+			return fmt.Errorf("%s failed after %d attempts: %w", operation, attempt, err)
+		}
+
+		wait := delay
+		var statusErr *HTTPStatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > wait {
+			wait = statusErr.RetryAfter
+		}
+		log.Warn().Err(err).Int("attempt", attempt).Dur("retryIn", wait).Msgf("%s failed, retrying", operation)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
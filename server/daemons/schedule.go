@@ -0,0 +1,159 @@
+package daemons
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduleMacros maps the supported shorthands to their cron expression.
+var scheduleMacros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// importSchedule is a parsed cron expression with the five standard fields
+// (minute, hour, day of month, month, day of week). Each field is a bit set of
+// the values it allows.
+type importSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	// Like cron, when both day fields are restricted a time matches either.
+	dayOfMonthAny, dayOfWeekAny bool
+}
+
+// parseSchedule parses a cron expression such as "0 4 * * *" or "30 */6 * * 1-5".
+// Fields accept "*", values, ranges ("1-5"), steps ("*/15", "0-30/10") and
+// comma separated lists; day of week runs 0-7 with both 0 and 7 meaning Sunday.
+// The day fields also accept "?" for any day.
+func parseSchedule(expr string) (*importSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := scheduleMacros[expr]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q must have 5 fields (minute hour day-of-month month day-of-week)", expr)
+	}
+
+	for _, i := range []int{2, 4} {
+		if fields[i] == "?" {
+			fields[i] = "*"
+		}
+	}
+
+	var s importSchedule
+	var err error
+	if s.minute, err = parseScheduleField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("schedule %q minute: %w", expr, err)
+	}
+	if s.hour, err = parseScheduleField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("schedule %q hour: %w", expr, err)
+	}
+	if s.dayOfMonth, err = parseScheduleField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("schedule %q day of month: %w", expr, err)
+	}
+	if s.month, err = parseScheduleField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("schedule %q month: %w", expr, err)
+	}
+	if s.dayOfWeek, err = parseScheduleField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("schedule %q day of week: %w", expr, err)
+	}
+	if s.dayOfWeek&(1<<7) != 0 {
+		s.dayOfWeek |= 1 << 0
+	}
+	// A day field is unrestricted when it allows every day, however it is
+	// spelled ("*", "*/1", "1-31", "0-6"...).
+	s.dayOfMonthAny = s.dayOfMonth == scheduleRange(1, 31)
+	s.dayOfWeekAny = s.dayOfWeek&scheduleRange(0, 6) == scheduleRange(0, 6)
+
+	return &s, nil
+}
+
+// parseScheduleField parses one cron field into a bit set of allowed values.
+func parseScheduleField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangePart = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rangePart)
+			}
+			lo, hi = value, value
+			if step > 1 {
+				hi = max // "5/15" means every 15 starting at 5
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// scheduleRange returns the bit set of all values from lo to hi.
+func scheduleRange(lo, hi int) uint64 {
+	var bits uint64
+	for v := lo; v <= hi; v++ {
+		bits |= 1 << uint(v)
+	}
+	return bits
+}
+
+// matches reports whether t (in its own location) falls on the schedule.
+func (s *importSchedule) matches(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 || s.hour&(1<<uint(t.Hour())) == 0 || s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	domMatch := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dowMatch := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.dayOfMonthAny || s.dayOfWeekAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next returns the first scheduled minute after after, or the zero time when the
+// schedule never fires (for example "0 0 30 2 *").
+func (s *importSchedule) next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	// Scanning minute by minute is cheap for a schedule evaluated once per run;
+	// five years covers every combination of month length and weekday.
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.matches(t) {
+			return t
+		}
+		t = t.Add(time.Minute)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,84 @@
+package daemons
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{name: "too few fields", expr: "0 4 * *"},
+		{name: "too many fields", expr: "0 4 * * * *"},
+		{name: "unknown macro", expr: "@yearly"},
+		{name: "minute out of range", expr: "60 * * * *"},
+		{name: "hour out of range", expr: "0 24 * * *"},
+		{name: "day of month zero", expr: "0 0 0 * *"},
+		{name: "month out of range", expr: "0 0 * 13 *"},
+		{name: "day of week out of range", expr: "0 0 * * 8"},
+		{name: "reversed range", expr: "5-1 * * * *"},
+		{name: "zero step", expr: "*/0 * * * *"},
+		{name: "invalid step", expr: "*/x * * * *"},
+		{name: "invalid value", expr: "a * * * *"},
+		{name: "invalid range", expr: "1-a * * * *"},
+		{name: "question mark outside the day fields", expr: "? * * * *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s, err := parseSchedule(tt.expr); err == nil {
+				t.Fatalf("parseSchedule(%q) = %+v, want an error", tt.expr, s)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// 2024-01-01 is a Monday.
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{name: "daily at a fixed time", expr: "0 4 * * *", after: at(1, 1, 10, 0), want: at(1, 2, 4, 0)},
+		{name: "strictly after the given minute", expr: "0 4 * * *", after: at(1, 1, 4, 0), want: at(1, 2, 4, 0)},
+		{name: "seconds are ignored", expr: "* * * * *", after: at(1, 1, 10, 0).Add(30 * time.Second), want: at(1, 1, 10, 1)},
+		{name: "macro", expr: "@daily", after: at(1, 1, 10, 0), want: at(1, 2, 0, 0)},
+		{name: "weekly macro", expr: "@weekly", after: at(1, 1, 0, 0), want: at(1, 7, 0, 0)},
+		{name: "monthly macro", expr: "@monthly", after: at(1, 1, 0, 0), want: at(2, 1, 0, 0)},
+		{name: "step over the whole field", expr: "*/15 * * * *", after: at(1, 1, 10, 7), want: at(1, 1, 10, 15)},
+		{name: "step from a start value", expr: "5/15 * * * *", after: at(1, 1, 10, 21), want: at(1, 1, 10, 35)},
+		{name: "step over a range", expr: "0-30/10 * * * *", after: at(1, 1, 10, 31), want: at(1, 1, 11, 0)},
+		{name: "list", expr: "0 9,17 * * *", after: at(1, 1, 10, 0), want: at(1, 1, 17, 0)},
+		{name: "range of weekdays", expr: "30 */6 * * 1-5", after: at(1, 5, 19, 0), want: at(1, 8, 0, 30)},
+		{name: "month", expr: "0 0 1 3 *", after: at(1, 1, 0, 0), want: at(3, 1, 0, 0)},
+		{name: "sunday as 7", expr: "0 0 * * 7", after: at(1, 1, 0, 0), want: at(1, 7, 0, 0)},
+		{name: "question mark as day of month", expr: "0 0 ? * 0", after: at(1, 1, 0, 0), want: at(1, 7, 0, 0)},
+		{name: "question mark as day of week", expr: "0 0 13 * ?", after: at(1, 1, 0, 0), want: at(1, 13, 0, 0)},
+		{name: "both days restricted match either", expr: "0 0 13 * 5", after: at(1, 1, 0, 0), want: at(1, 5, 0, 0)},
+		{name: "both days restricted match the day of month", expr: "0 0 13 * 5", after: at(1, 12, 0, 0), want: at(1, 13, 0, 0)},
+		{name: "every day of month spelled as a range", expr: "0 0 1-31 * 1", after: at(1, 1, 0, 0), want: at(1, 8, 0, 0)},
+		{name: "every day of month spelled as a step", expr: "0 0 */1 * 1", after: at(1, 1, 0, 0), want: at(1, 8, 0, 0)},
+		{name: "every day of week spelled as a range", expr: "0 0 13 * 0-6", after: at(1, 1, 0, 0), want: at(1, 13, 0, 0)},
+		{name: "every day of week including 7", expr: "0 0 13 * 0-7", after: at(1, 1, 0, 0), want: at(1, 13, 0, 0)},
+		{name: "leap day", expr: "0 0 29 2 *", after: at(3, 1, 0, 0), want: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "no match in february", expr: "0 0 30 2 *", after: at(1, 1, 0, 0), want: time.Time{}},
+		{name: "no match in a 30-day month", expr: "0 0 31 4 *", after: at(1, 1, 0, 0), want: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("parseSchedule(%q): %v", tt.expr, err)
+			}
+			if got := s.next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("next(%v) for %q = %v, want %v", tt.after, tt.expr, got, tt.want)
+			}
+		})
+	}
+}
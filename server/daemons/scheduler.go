@@ -0,0 +1,86 @@
+package daemons
+
+import (
+	"context"
+	"errors"
+	"magic-helper/settings"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// scheduleRetryDelay is the wait after the first failed scheduled import; it
+	// doubles per consecutive failure up to scheduleRetryMaxDelay.
+	scheduleRetryDelay = 5 * time.Minute
+	// scheduleRetryMaxDelay caps the wait before retrying a failed import.
+	scheduleRetryMaxDelay = 6 * time.Hour
+)
+
+// RunImportScheduler runs an import at startup and then at every slot of the
+// cron schedule in settings.Import.Schedule, always through the ImportManager so
+// it never overlaps a manual import. A slot that finds an import already running
+// is skipped. A failed import is retried with a growing delay, but never later
+// than the next slot. The schedule "off" disables scheduled imports.
+func RunImportScheduler() {
+	expr := settings.Current.Import.Schedule
+	if strings.EqualFold(strings.TrimSpace(expr), "off") {
+		log.Info().Msg("Scheduled imports are disabled")
+		return
+	}
+	schedule, err := parseSchedule(expr)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid import schedule, scheduled imports are disabled")
+		return
+	}
+	log.Info().Str("schedule", expr).Msg("Starting import scheduler")
+
+	m := GetImportManager()
+	failures := 0
+	for {
+		err := runScheduledImport(m)
+
+		now := time.Now()
+		next := schedule.next(now)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			failures++
+			retryAt := now.Add(scheduleRetryDelayFor(failures))
+			if next.IsZero() || retryAt.Before(next) {
+				next = retryAt
+			}
+			log.Error().Err(err).Int("failures", failures).Time("retryAt", next).Msg("Scheduled import failed")
+		} else {
+			failures = 0
+		}
+
+		m.setNextRun(next)
+		if next.IsZero() {
+			log.Warn().Str("schedule", expr).Msg("Import schedule has no future run, scheduler stopped")
+			return
+		}
+		log.Info().Time("nextRun", next).Msg("Next scheduled import")
+		time.Sleep(time.Until(next))
+	}
+}
+
+// runScheduledImport starts a scheduled import and waits for it to finish. It
+// returns nil without importing when another import is already running.
+func runScheduledImport(m *ImportManager) error {
+	done, ok := m.start(DefaultImportSource(), ImportTriggerScheduled)
+	if !ok {
+		log.Info().Msg("Import already in progress, skipping scheduled import")
+		return nil
+	}
+	return <-done
+}
+
+// scheduleRetryDelayFor returns the wait before retrying after the given number
+// of consecutive failed imports.
+func scheduleRetryDelayFor(failures int) time.Duration {
+	delay := scheduleRetryDelay
+	for i := 1; i < failures && delay < scheduleRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, scheduleRetryMaxDelay)
+}
@@ -0,0 +1,153 @@
+package daemons
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"magic-helper/arango"
+	"magic-helper/settings"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// setIconManifestFile lists, per set code, the URI each mirrored icon was
+// downloaded from. Scryfall versions icon URIs with a query string, so a
+// different URI means the icon changed.
+const setIconManifestFile = "icons.json"
+
+// mirrorSetIcons downloads the icon of every set in mtg_sets into the configured
+// set icon directory when the file is missing or its icon URI changed. Sets that
+// share an icon URI download it once. A failed icon is logged and left for the
+// next import; only cancellation is returned as an error.
+func mirrorSetIcons(ctx context.Context) error {
+	dir := settings.Current.Import.SetIconDir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Error().Err(err).Str("dir", dir).Msg("Error creating set icon directory")
+		return err
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+		FOR s IN mtg_sets
+			FILTER s.iconSVGURI != null AND s.iconSVGURI != ""
+			RETURN { code: s._key, uri: s.iconSVGURI }
+	`)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("Error querying set icons")
+		return err
+	}
+	defer cursor.Close()
+
+	type setIcon struct {
+		Code string `json:"code"`
+		URI  string `json:"uri"`
+	}
+	var icons []setIcon
+	for cursor.HasMore() {
+		var icon setIcon
+		if _, err := cursor.ReadDocument(ctx, &icon); err != nil {
+			log.Error().Err(err).Msg("Error reading set icon")
+			return err
+		}
+		icons = append(icons, icon)
+	}
+
+	manifest := readSetIconManifest(dir)
+	downloaded := map[string][]byte{}
+	mirrored, failed := 0, 0
+	for _, icon := range icons {
+		path := filepath.Join(dir, icon.Code+".svg")
+		if _, err := os.Stat(path); err == nil && manifest[icon.Code] == icon.URI {
+			continue
+		}
+
+		body, ok := downloaded[icon.URI]
+		if !ok {
+			if len(downloaded) > 0 {
+				// Stay within Scryfall's request rate guidelines.
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(100 * time.Millisecond):
+				}
+			}
+			body, err = fetchBodyWithContext(ctx, icon.URI)
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			if err != nil {
+				log.Warn().Err(err).Str("set", icon.Code).Msg("Error downloading set icon")
+				failed++
+				continue
+			}
+			downloaded[icon.URI] = body
+		}
+
+		if err := writeFileAtomic(path, body); err != nil {
+			log.Warn().Err(err).Str("set", icon.Code).Msg("Error writing set icon")
+			failed++
+			continue
+		}
+		manifest[icon.Code] = icon.URI
+		mirrored++
+	}
+
+	if mirrored > 0 {
+		if err := writeSetIconManifest(dir, manifest); err != nil {
+			log.Error().Err(err).Str("dir", dir).Msg("Error writing set icon manifest")
+		}
+	}
+
+	log.Info().Int("mirrored", mirrored).Int("failed", failed).Int("sets", len(icons)).Msg("Set icons mirrored")
+	return nil
+}
+
+// readSetIconManifest loads the icon manifest of dir; a missing or unreadable
+// manifest yields an empty one, so every icon is downloaded again.
+func readSetIconManifest(dir string) map[string]string {
+	manifest := map[string]string{}
+	data, err := os.ReadFile(filepath.Join(dir, setIconManifestFile))
+	if err != nil {
+		return manifest
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		log.Warn().Err(err).Str("dir", dir).Msg("Ignoring unreadable set icon manifest")
+		return map[string]string{}
+	}
+	return manifest
+}
+
+// writeSetIconManifest stores the icon manifest of dir.
+func writeSetIconManifest(dir string, manifest map[string]string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, setIconManifestFile), data)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never see a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
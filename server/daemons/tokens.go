@@ -0,0 +1,72 @@
+package daemons
+
+import (
+	"context"
+	"magic-helper/arango"
+	"magic-helper/graph/model/scryfall"
+
+	"github.com/rs/zerolog/log"
+)
+
+// collectTokens builds the mtg_tokens catalog from mtg_original_cards. Tokens,
+// double-faced tokens and emblems are grouped like cards, except that
+// double-faced tokens are keyed by the oracle IDs of both faces, since many of
+// them share a front face.
+func collectTokens(ctx context.Context) ([]scryfall.MTG_CardDB, error) {
+	log.Info().Msg("Collecting tokens")
+
+	aq := arango.NewQuery( /* aql */ `
+		FOR c IN mtg_original_cards
+			FILTER c.layout IN ["token", "double_faced_token", "emblem"]
+			FILTER c.lang IN @languages
+			FILTER "paper" IN c.games OR "mtgo" IN c.games OR "arena" IN c.games
+			FILTER c.oversized == false
+			LET groupKey = c.oracle_id != null ? c.oracle_id : CONCAT_SEPARATOR("_", c.card_faces[*].oracle_id)
+			FILTER groupKey != ""
+			SORT DATE_TIMESTAMP(c.released_at) ASC, c.id ASC
+			COLLECT key = groupKey INTO cards = UNSET(c, "_key", "_id", "_rev")
+			RETURN { key: key, cards: cards }
+	`)
+	aq.AddBindVar("languages", importLanguages())
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("Error querying tokens")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	var tokens []scryfall.MTG_CardDB
+	for cursor.HasMore() {
+		var group struct {
+			Key   string          `json:"key"`
+			Cards []scryfall.Card `json:"cards"`
+		}
+		if _, err := cursor.ReadDocument(ctx, &group); err != nil {
+			log.Warn().Err(err).Msg("Skipping unreadable token group")
+			continue
+		}
+		if len(group.Cards) == 0 {
+			continue
+		}
+
+		token := buildCardGroup(group.Cards)
+		token.ID = group.Key
+		tokens = append(tokens, token)
+	}
+
+	log.Info().Msgf("Collected %d tokens", len(tokens))
+	return tokens, nil
+}
+
+// syncTokens writes tokens to mtg_tokens with the same diff as cards.
+func syncTokens(ctx context.Context, tokens []scryfall.MTG_CardDB) error {
+	stats, err := syncCards(ctx, arango.MTG_TOKENS_COLLECTION, tokens, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("Error syncing tokens")
+		return err
+	}
+
+	log.Info().Msgf("Finished syncing %d tokens: %s.", len(tokens), stats)
+	return nil
+}
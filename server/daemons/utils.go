@@ -3,67 +3,52 @@ package daemons
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"io"
 	"magic-helper/arango"
 	"magic-helper/graph/model"
+	"magic-helper/graph/model/scryfall"
+	"magic-helper/settings"
 	"magic-helper/util"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
-// shouldDownloadStart returns whether a new fetch cycle should begin for a record
-// based on ApplicationConfig's LastTimeFetched; creates the record when missing.
-func shouldDownloadStart(record string) (bool, error) {
-	var appConfig model.MTGApplicationConfig
+// readFetchState returns what was last fetched for a record (ETag, bulk item
+// metadata); a missing record yields an empty state that fetches everything.
+func readFetchState(record string) (model.MTGApplicationConfig, error) {
+	state := model.MTGApplicationConfig{ID: record}
 	ctx := context.Background()
 
 	col, err := arango.EnsureDocumentCollection(ctx, arango.APPLICATION_CONFIG_COLLECTION)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error ensuring application config collection")
-		return false, err
+		return state, err
 	}
 
 	exists, err := col.DocumentExists(ctx, record)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error checking if document exists")
-		return false, err
+		return state, err
+	}
+	if !exists {
+		return state, nil
 	}
 
-	if exists {
-		_, err := col.ReadDocument(ctx, record, &appConfig)
-		if err != nil {
-			log.Error().Err(err).Msgf("Error reading document")
-			return false, err
-		}
-
-		log.Info().Str("record", record).Msgf("Last time fetched: %v", appConfig.LastTimeFetched)
-
-		// If we fetched sets in the last 24 hours, don't fetch again
-		if util.Now() < appConfig.LastTimeFetched+24*60*60*1000 {
-			log.Info().Str("record", record).Msg("Already fetched in the last 24 hours")
-			return false, nil
-		} else {
-			return true, nil
-		}
-	} else {
-		appConfig = model.MTGApplicationConfig{
-			ID:              record,
-			LastTimeFetched: 0,
-		}
-		_, err := col.CreateDocument(ctx, appConfig)
-		if err != nil {
-			log.Error().Err(err).Msgf("Error creating document")
-			return false, err
-		}
-		return true, nil
+	if _, err := col.ReadDocument(ctx, record, &state); err != nil {
+		log.Error().Err(err).Msgf("Error reading document")
+		return state, err
 	}
+
+	log.Info().Str("record", record).Str("etag", state.ETag).Str("bulkUpdatedAt", state.BulkUpdatedAt).Msgf("Last time fetched: %v", state.LastTimeFetched)
+	return state, nil
 }
 
-// resetLastTimeFetched sets ApplicationConfig's LastTimeFetched to 0 for a record,
-// forcing the next fetch cycle to run regardless of when the last fetch occurred.
-func resetLastTimeFetched(record string) error {
+// saveFetchState stores state as the last successful fetch of its record.
+func saveFetchState(state model.MTGApplicationConfig) error {
 	ctx := context.Background()
 
 	col, err := arango.EnsureDocumentCollection(ctx, arango.APPLICATION_CONFIG_COLLECTION)
@@ -72,40 +57,40 @@ func resetLastTimeFetched(record string) error {
 		return err
 	}
 
-	exists, err := col.DocumentExists(ctx, record)
+	state.LastTimeFetched = util.Now()
+
+	exists, err := col.DocumentExists(ctx, state.ID)
 	if err != nil {
 		log.Error().Err(err).Msgf("Error checking if document exists")
 		return err
 	}
-
-	if !exists {
-		// Create the record with 0 timestamp
-		appConfig := model.MTGApplicationConfig{
-			ID:              record,
-			LastTimeFetched: 0,
-		}
-		_, err := col.CreateDocument(ctx, appConfig)
-		return err
-	}
-
-	// Update existing record to 0
-	appConfig := model.MTGApplicationConfig{
-		ID:              record,
-		LastTimeFetched: 0,
+	if exists {
+		_, err = col.ReplaceDocument(ctx, state.ID, state)
+	} else {
+		_, err = col.CreateDocument(ctx, state)
 	}
-	_, err = col.UpdateDocument(ctx, record, appConfig)
 	if err != nil {
-		log.Error().Err(err).Msgf("Error resetting last time fetched")
+		log.Error().Err(err).Msgf("Error saving fetch state")
 		return err
 	}
 
-	log.Info().Str("record", record).Msg("Reset last time fetched to 0")
 	return nil
 }
 
-// updateLastTimeFetched updates ApplicationConfig's LastTimeFetched for a record.
-func updateLastTimeFetched(record string) error {
-	var appConfig model.MTGApplicationConfig
+// commitFetchStates saves the fetch states a card source returned, once the data
+// they describe is stored. A failure only means the data is imported again on
+// the next run, so it is logged and not returned.
+func commitFetchStates(states []model.MTGApplicationConfig) {
+	for _, state := range states {
+		if err := saveFetchState(state); err != nil {
+			log.Error().Err(err).Str("record", state.ID).Msg("Error saving fetch state")
+		}
+	}
+}
+
+// resetFetchState forgets what was last fetched for a record, forcing the next
+// fetch cycle to download and process everything again.
+func resetFetchState(record string) error {
 	ctx := context.Background()
 
 	col, err := arango.EnsureDocumentCollection(ctx, arango.APPLICATION_CONFIG_COLLECTION)
@@ -114,62 +99,63 @@ func updateLastTimeFetched(record string) error {
 		return err
 	}
 
-	_, err = col.ReadDocument(ctx, record, &appConfig)
+	exists, err := col.DocumentExists(ctx, record)
 	if err != nil {
-		log.Error().Err(err).Msgf("Error reading document")
+		log.Error().Err(err).Msgf("Error checking if document exists")
 		return err
 	}
+	if !exists {
+		return nil
+	}
 
-	appConfig.LastTimeFetched = util.Now()
-
-	_, err = col.UpdateDocument(ctx, record, appConfig)
+	_, err = col.ReplaceDocument(ctx, record, model.MTGApplicationConfig{ID: record})
 	if err != nil {
-		log.Error().Err(err).Msgf("Error updating document")
+		log.Error().Err(err).Msgf("Error resetting fetch state")
 		return err
 	}
 
+	log.Info().Str("record", record).Msg("Reset fetch state")
 	return nil
 }
 
-// createScryfallRequest builds a basic GET request for Scryfall with headers.
-func createScryfallRequest(url string) (*http.Request, error) {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Error().Err(err).Msgf("Error creating request to Scryfall")
-		return nil, err
-	}
-	req.Header.Set("User-Agent", "MagicHelper/0.1")
-	req.Header.Set("Accept", "application/json")
-
-	return req, nil
-}
-
 // parseCardsFromRaw converts raw JSON items into generic card maps for upsert.
-func parseCardsFromRaw(allCards []json.RawMessage) ([]map[string]any, error) {
+func parseCardsFromRaw(allCards []json.RawMessage, runID string) ([]map[string]any, []model.MTGQuarantinedRecordDB) {
 	cards := []map[string]any{}
+	var quarantined []model.MTGQuarantinedRecordDB
 	for _, card := range allCards {
+		// Decoding into the typed card catches fields of the wrong type.
+		var typed scryfall.Card
+		err := json.Unmarshal(card, &typed)
+		if err == nil {
+			err = validateCard(typed)
+		}
 		var cardMap map[string]any
-		err := json.Unmarshal(card, &cardMap)
+		if err == nil {
+			err = json.Unmarshal(card, &cardMap)
+		}
 		if err != nil {
 			log.Error().Err(err).Str("card", string(card)).Msgf("Error unmarshalling card")
-			return nil, err
+			quarantined = append(quarantined, quarantineRecord(model.MtgQuarantineKindCard, probeRecordID(card), card, err, runID))
+			continue
 		}
 		cards = append(cards, cardMap)
 	}
-	return cards, nil
+	return cards, quarantined
 }
 
-// upsertOriginalCards upserts the provided card maps into mtg_original_cards.
-func upsertOriginalCards(ctx context.Context, cards []map[string]any) error {
+// upsertOriginalCards stores the provided card maps in mtg_original_cards, tagged
+// with the import run that saw them. Existing documents are replaced rather than
+// merged, so fields Scryfall dropped do not linger.
+func upsertOriginalCards(ctx context.Context, cards []map[string]any, runID string) error {
 	aq := arango.NewQuery( /* aql */ `
 		FOR c IN @cards
-			UPSERT { _key: c.id }
-			INSERT MERGE({ _key: c.id }, c)
-			UPDATE c
-			IN mtg_original_cards
+			INSERT MERGE(c, { _key: c.id, importRunID: @runID })
+			INTO mtg_original_cards
+			OPTIONS { overwriteMode: "replace" }
 	`)
 
 	aq.AddBindVar("cards", cards)
+	aq.AddBindVar("runID", runID)
 
 	_, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
 	if err != nil {
@@ -180,31 +166,185 @@ func upsertOriginalCards(ctx context.Context, cards []map[string]any) error {
 	return nil
 }
 
+// pruneOriginalCards removes the mtg_original_cards the run with runID did not
+// see, which Scryfall has deleted or re-IDed since. Unless import.pruneOriginals is
+// "delete" they are moved to mtg_original_cards_archive first. It returns the
+// number of documents pruned.
+func pruneOriginalCards(ctx context.Context, runID string) (int, error) {
+	query := /* aql */ `
+		FOR c IN mtg_original_cards
+			FILTER c.importRunID != @runID
+			REMOVE c IN mtg_original_cards
+			COLLECT WITH COUNT INTO pruned
+			RETURN pruned
+	`
+	if settings.Current.Import.PruneOriginals != "delete" {
+		query = /* aql */ `
+			FOR c IN mtg_original_cards
+				FILTER c.importRunID != @runID
+				INSERT MERGE(UNSET(c, "_id", "_rev"), {
+					archivedAt: DATE_ISO8601(DATE_NOW()),
+					archivedByRunID: @runID
+				}) INTO mtg_original_cards_archive
+				OPTIONS { overwriteMode: "replace" }
+				REMOVE c IN mtg_original_cards
+				COLLECT WITH COUNT INTO pruned
+				RETURN pruned
+		`
+	}
+	aq := arango.NewQuery(query)
+	aq.AddBindVar("runID", runID)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("Error pruning original cards")
+		return 0, err
+	}
+	defer cursor.Close()
+
+	pruned := 0
+	if cursor.HasMore() {
+		if _, err := cursor.ReadDocument(ctx, &pruned); err != nil {
+			return 0, err
+		}
+	}
+	return pruned, nil
+}
+
+// scryfallAPIURL returns the URL of path (such as "/sets") on the configured
+// Scryfall API.
+func scryfallAPIURL(path string) string {
+	return settings.Current.Scryfall.BaseURL + path
+}
+
+// scryfallPacer remembers when the last Scryfall API request was sent.
+var scryfallPacer struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+// waitForScryfallSlot blocks until scryfall.requestDelayMs has passed since the
+// previous API request. Requests to other hosts, such as the bulk file and
+// image CDNs, are not paced.
+func waitForScryfallSlot(ctx context.Context, url string) error {
+	delay := time.Duration(settings.Current.Scryfall.RequestDelayMs) * time.Millisecond
+	if delay <= 0 || !strings.HasPrefix(url, settings.Current.Scryfall.BaseURL) {
+		return nil
+	}
+
+	scryfallPacer.mu.Lock()
+	defer scryfallPacer.mu.Unlock()
+	if wait := time.Until(scryfallPacer.last.Add(delay)); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	scryfallPacer.last = time.Now()
+	return nil
+}
+
+// doScryfallRequest sends req once the request pacing allows it.
+func doScryfallRequest(req *http.Request) (*http.Response, error) {
+	if err := waitForScryfallSlot(req.Context(), req.URL.String()); err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
+
 // createScryfallRequestWithContext builds a GET request bound to the given context.
 func createScryfallRequestWithContext(ctx context.Context, url string) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Set("User-Agent", settings.Current.Scryfall.UserAgent)
+	req.Header.Set("Accept", "application/json")
 	return req, nil
 }
 
-// fetchURLWithContext performs an HTTP GET with context and ensures 200 OK.
-func fetchURLWithContext(ctx context.Context, url string) (*http.Response, error) {
+// getURL performs a single HTTP GET with context and ensures 200 OK. A non-200
+// status is returned as *HTTPStatusError.
+func getURL(ctx context.Context, url string) (*http.Response, error) {
 	req, err := createScryfallRequestWithContext(ctx, url)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := doScryfallRequest(req)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %s", resp.Status)
+		resp.Body.Close()
+		return nil, newHTTPStatusError(url, resp)
 	}
 	return resp, nil
 }
 
+// fetchURLWithContext performs an HTTP GET with context and ensures 200 OK,
+// retrying transient failures with exponential backoff.
+func fetchURLWithContext(ctx context.Context, url string) (*http.Response, error) {
+	var resp *http.Response
+	err := retryWithBackoff(ctx, "GET "+url, func() error {
+		var err error
+		resp, err = getURL(ctx, url)
+		return err
+	})
+	return resp, err
+}
+
+// fetchBodyIfChanged GETs url with If-None-Match set to etag (when known) and
+// reads the whole body, retrying transient failures. changed is false when the
+// server answered 304 Not Modified; newETag is the ETag of the response.
+func fetchBodyIfChanged(ctx context.Context, url string, etag string) (body []byte, newETag string, changed bool, err error) {
+	err = retryWithBackoff(ctx, "GET "+url, func() error {
+		req, err := createScryfallRequestWithContext(ctx, url)
+		if err != nil {
+			return err
+		}
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp, err := doScryfallRequest(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusNotModified:
+			body, newETag, changed = nil, etag, false
+			return nil
+		case http.StatusOK:
+			body, err = io.ReadAll(resp.Body)
+			newETag, changed = resp.Header.Get("ETag"), true
+			return err
+		default:
+			return newHTTPStatusError(url, resp)
+		}
+	})
+	return body, newETag, changed, err
+}
+
+// fetchBodyWithContext GETs url and reads the whole body, retrying transient
+// failures of both the request and the read.
+func fetchBodyWithContext(ctx context.Context, url string) ([]byte, error) {
+	var body []byte
+	err := retryWithBackoff(ctx, "GET "+url, func() error {
+		resp, err := getURL(ctx, url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		body, err = io.ReadAll(resp.Body)
+		return err
+	})
+	return body, err
+}
+
 // SanitizeFilename replaces filesystem-invalid characters for safer filenames.
 func SanitizeFilename(name string) string {
 	// Example: Replace slashes with underscores
@@ -1,9 +1,153 @@
 package model
 
-// MTGApplicationConfig stores housekeeping information such as last fetch timestamps.
+import "strings"
+
+// MTGApplicationConfig stores housekeeping information such as last fetch
+// timestamps and what was fetched, so unchanged Scryfall data can be skipped.
 type MTGApplicationConfig struct {
 	ID              string `json:"_key"`
 	LastTimeFetched int    `json:"last_time_fetched"`
+	ETag            string `json:"etag,omitempty"`            // ETag of the last fetched list response
+	BulkType        string `json:"bulk_type,omitempty"`       // bulk dataset the cards were imported from
+	BulkUpdatedAt   string `json:"bulk_updated_at,omitempty"` // updated_at of that bulk item
+	BulkSize        int64  `json:"bulk_size,omitempty"`       // size of that bulk item in bytes
+}
+
+// MTGImportRunDB records one finished import run, manual or scheduled.
+type MTGImportRunDB struct {
+	ID          *string                  `json:"_key,omitempty"`
+	Trigger     string                   `json:"trigger"` // "manual" or "scheduled"
+	Status      string                   `json:"status"`  // "complete" or "failed"
+	StartedAt   string                   `json:"startedAt"`
+	FinishedAt  string                   `json:"finishedAt"`
+	DurationMs  int64                    `json:"durationMs"`
+	Error       string                   `json:"error,omitempty"`
+	FailedPhase string                   `json:"failedPhase,omitempty"`
+	Phases      []MTGImportPhaseTimingDB `json:"phases"`
+	Sets        MTGImportCountsDB        `json:"sets"`
+	Cards       MTGImportCountsDB        `json:"cards"`
+	// OriginalsPruned counts the mtg_original_cards the run no longer saw.
+	OriginalsPruned int `json:"originalsPruned"`
+	// RecordsQuarantined counts the malformed records the run set aside.
+	RecordsQuarantined int `json:"recordsQuarantined"`
+}
+
+// ToModel converts the stored run to its GraphQL form. Phases are stored in their
+// lowercase daemon form and map onto the uppercase MTG_ImportPhase values.
+func (db *MTGImportRunDB) ToModel() *MtgImportRun {
+	if db == nil {
+		return nil
+	}
+	run := &MtgImportRun{
+		ID:         *db.ID,
+		Trigger:    MtgImportTrigger(strings.ToUpper(db.Trigger)),
+		Status:     MtgImportPhase(strings.ToUpper(db.Status)),
+		StartedAt:  db.StartedAt,
+		FinishedAt: db.FinishedAt,
+		DurationMs: int(db.DurationMs),
+		Phases:     make([]*MtgImportPhaseTiming, 0, len(db.Phases)),
+		Sets:       db.Sets.ToModel(),
+		Cards:      db.Cards.ToModel(),
+
+		OriginalsPruned:    db.OriginalsPruned,
+		RecordsQuarantined: db.RecordsQuarantined,
+	}
+	if db.Error != "" {
+		run.Error = &db.Error
+	}
+	if db.FailedPhase != "" {
+		failedPhase := MtgImportPhase(strings.ToUpper(db.FailedPhase))
+		run.FailedPhase = &failedPhase
+	}
+	for _, phase := range db.Phases {
+		run.Phases = append(run.Phases, &MtgImportPhaseTiming{
+			Phase:      MtgImportPhase(strings.ToUpper(phase.Phase)),
+			StartedAt:  phase.StartedAt,
+			DurationMs: int(phase.DurationMs),
+		})
+	}
+	return run
+}
+
+// MTGCatalogChangeDB records what one import changed about a card in mtg_cards.
+// The document key is generated on insert.
+type MTGCatalogChangeDB struct {
+	CardID       string                   `json:"cardID"`
+	Name         string                   `json:"name"`
+	ImportedAt   string                   `json:"importedAt"`
+	Added        bool                     `json:"added"`
+	FieldChanges []*MtgCatalogFieldChange `json:"fieldChanges"`
+	NewVersions  []*MtgCatalogNewVersion  `json:"newVersions"`
+}
+
+// MTGLegalityChangeDB records one card changing legality in one format. The
+// document key is generated on insert.
+type MTGLegalityChangeDB struct {
+	CardID    string  `json:"cardID"`
+	Name      string  `json:"name"`
+	Format    string  `json:"format"`
+	Before    *string `json:"before"`
+	After     *string `json:"after"`
+	ChangedAt string  `json:"changedAt"`
+}
+
+// MTGImportPhaseTimingDB records when a phase of an import run started and how long it took.
+type MTGImportPhaseTimingDB struct {
+	Phase      string `json:"phase"`
+	StartedAt  string `json:"startedAt"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// MTGImportCountsDB counts how an import run changed a collection.
+type MTGImportCountsDB struct {
+	Added     int `json:"added"`
+	Updated   int `json:"updated"`
+	Removed   int `json:"removed"`
+	Unchanged int `json:"unchanged"`
+}
+
+// ToModel converts the stored counts to their GraphQL form.
+func (db MTGImportCountsDB) ToModel() *MtgImportRunCounts {
+	return &MtgImportRunCounts{
+		Added:     db.Added,
+		Updated:   db.Updated,
+		Removed:   db.Removed,
+		Unchanged: db.Unchanged,
+	}
+}
+
+// MTGCardPriceDB is the daily price snapshot of one card version, keyed by
+// "<versionID>_<date>" so a day is only stored once.
+type MTGCardPriceDB struct {
+	ID        string    `json:"_key"`
+	VersionID string    `json:"versionID"`
+	Date      string    `json:"date"`
+	Prices    MtgPrices `json:"prices"`
+}
+
+// ToModel converts the snapshot to its GraphQL form.
+func (db *MTGCardPriceDB) ToModel() *MtgPricePoint {
+	prices := db.Prices
+	return &MtgPricePoint{
+		Date:   db.Date,
+		Prices: &prices,
+	}
+}
+
+// MTGCardRulingsDB holds every ruling of one card, keyed by its oracle ID.
+type MTGCardRulingsDB struct {
+	ID      string       `json:"_key"`
+	Rulings []*MtgRuling `json:"rulings"`
+}
+
+// MTGCardRelatedCardDB links a card to a card named in the all_parts of one of
+// its printings. Component is the role of the _to card (token, meld_part,
+// meld_result or combo_piece).
+type MTGCardRelatedCardDB struct {
+	ID        string `json:"_key"`
+	From      string `json:"_from"`
+	To        string `json:"_to"`
+	Component string `json:"component"`
 }
 
 // MTGDeckDB is the persisted form of a deck document in ArangoDB.
@@ -13,6 +157,7 @@ type MTGDeckDB struct {
 	Zones    []FlowZone `json:"zones"`
 	Type     DeckType   `json:"type"`
 	Autosave bool       `json:"autosave"`
+	SavedAt  string     `json:"savedAt,omitempty"`
 }
 
 // MTGDeckFrontCardImageDB is an edge storing the chosen front image for a deck.
@@ -111,3 +256,16 @@ func (db *MTGTagDB) ToModel() *MtgTag {
 		Meta: db.Meta,
 	}
 }
+
+// MTGQuarantinedRecordDB is a malformed upstream record an import set aside in
+// mtg_quarantine. The key is derived from the kind and record ID, so a record
+// rejected again by a later run replaces its earlier entry.
+type MTGQuarantinedRecordDB struct {
+	ID            string            `json:"_key"`
+	RunID         string            `json:"runID"`
+	Kind          MtgQuarantineKind `json:"kind"`
+	RecordID      *string           `json:"recordID,omitempty"`
+	Raw           string            `json:"raw"`
+	Error         string            `json:"error"`
+	QuarantinedAt string            `json:"quarantinedAt"`
+}
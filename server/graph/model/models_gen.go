@@ -51,24 +51,36 @@ type MtgAssignTagToDeckInput struct {
 
 // Aggregated MTG card entity with curated versions and user context.
 type MtgCard struct {
-	ID             string              `json:"_key"`
-	Layout         MtgLayout           `json:"layout"`
-	Cmc            float64             `json:"CMC"`
-	ColorIdentity  []MtgColor          `json:"colorIdentity"`
-	ColorIndicator []string            `json:"colorIndicator,omitempty"`
-	Colors         []MtgColor          `json:"colors,omitempty"`
-	EDHRecRank     *int                `json:"EDHRecRank,omitempty"`
-	Keywords       []string            `json:"keywords"`
-	Loyalty        *string             `json:"loyalty,omitempty"`
-	ManaCost       *string             `json:"manaCost,omitempty"`
-	Name           string              `json:"name"`
-	OracleText     *string             `json:"oracleText,omitempty"`
-	Power          *string             `json:"power,omitempty"`
-	ProducedMana   []MtgColor          `json:"producedMana,omitempty"`
+	ID             string     `json:"_key"`
+	Layout         MtgLayout  `json:"layout"`
+	Cmc            float64    `json:"CMC"`
+	ColorIdentity  []MtgColor `json:"colorIdentity"`
+	ColorIndicator []string   `json:"colorIndicator,omitempty"`
+	Colors         []MtgColor `json:"colors,omitempty"`
+	// Defense of a battle.
+	Defense    *string `json:"defense,omitempty"`
+	EDHRecRank *int    `json:"EDHRecRank,omitempty"`
+	// True if the card is on the Commander Game Changer list.
+	GameChanger bool     `json:"gameChanger"`
+	Keywords    []string `json:"keywords"`
+	Loyalty     *string  `json:"loyalty,omitempty"`
+	ManaCost    *string  `json:"manaCost,omitempty"`
+	Name        string   `json:"name"`
+	OracleText  *string  `json:"oracleText,omitempty"`
+	// Popularity rank on Penny Dreadful, null when unranked.
+	PennyRank    *int       `json:"pennyRank,omitempty"`
+	Power        *string    `json:"power,omitempty"`
+	ProducedMana []MtgColor `json:"producedMana,omitempty"`
+	// True if the card is on the Reserved List.
+	Reserved       bool                `json:"reserved"`
 	Toughness      *string             `json:"toughness,omitempty"`
 	TypeLine       string              `json:"typeLine"`
 	Versions       []*MtgCardVersion   `json:"versions"`
 	TagAssignments []*MtgTagAssignment `json:"tagAssignments"`
+	// Official rulings and Scryfall notes for this card, oldest first.
+	Rulings []*MtgRuling `json:"rulings"`
+	// Cards linked to this one through Scryfall's all_parts, such as meld partners and results, combo pieces and created tokens.
+	RelatedCards []*MtgRelatedCard `json:"relatedCards"`
 }
 
 // One face of a multi-faced card version.
@@ -77,6 +89,7 @@ type MtgCardFace struct {
 	Cmc            *float64   `json:"CMC,omitempty"`
 	ColorIndicator []string   `json:"colorIndicator,omitempty"`
 	Colors         []MtgColor `json:"colors,omitempty"`
+	Defense        *string    `json:"defense,omitempty"`
 	FlavorText     *string    `json:"flavorText,omitempty"`
 	ImageUris      *MtgImage  `json:"imageUris,omitempty"`
 	Layout         *MtgLayout `json:"layout,omitempty"`
@@ -85,8 +98,15 @@ type MtgCardFace struct {
 	Name           string     `json:"name"`
 	OracleText     *string    `json:"oracleText,omitempty"`
 	Power          *string    `json:"power,omitempty"`
-	Toughness      *string    `json:"toughness,omitempty"`
-	TypeLine       *string    `json:"typeLine,omitempty"`
+	// Localized name printed on this face of non-English versions.
+	PrintedName *string `json:"printedName,omitempty"`
+	// Localized rules text printed on this face of non-English versions.
+	PrintedText *string `json:"printedText,omitempty"`
+	// Localized type line printed on this face of non-English versions.
+	PrintedTypeLine *string `json:"printedTypeLine,omitempty"`
+	Toughness       *string `json:"toughness,omitempty"`
+	TypeLine        *string `json:"typeLine,omitempty"`
+	Watermark       *string `json:"watermark,omitempty"`
 }
 
 // Minimal face data for dashboard UI.
@@ -96,8 +116,11 @@ type MtgCardFaceDashboard struct {
 
 // A specific printing/version of a card used by the app.
 type MtgCardVersion struct {
-	ID          string         `json:"ID"`
-	IsDefault   bool           `json:"isDefault"`
+	ID string `json:"ID"`
+	// Default version under the printing profile in effect, or under the built-in ordering without one.
+	IsDefault bool `json:"isDefault"`
+	// Position of this version in the built-in printing ordering, 0 being its default.
+	DefaultRank int            `json:"defaultRank"`
 	IsAlchemy   bool           `json:"isAlchemy"`
 	Artist      *string        `json:"artist,omitempty"`
 	Lang        string         `json:"lang"`
@@ -108,15 +131,45 @@ type MtgCardVersion struct {
 	Games       []MtgGame      `json:"games"`
 	ImageUris   *MtgImage      `json:"imageUris,omitempty"`
 	PrintedName string         `json:"printedName"`
-	Rarity      MtgRarity      `json:"rarity"`
-	ReleasedAt  string         `json:"releasedAt"`
-	Reprint     bool           `json:"reprint"`
-	SetName     string         `json:"setName"`
-	SetType     string         `json:"setType"`
-	Set         string         `json:"set"`
-	SetID       string         `json:"setID"`
-	Variation   bool           `json:"variation"`
-	VariationOf *string        `json:"variationOf,omitempty"`
+	// Localized rules text printed on non-English versions.
+	PrintedText *string `json:"printedText,omitempty"`
+	// Localized type line printed on non-English versions.
+	PrintedTypeLine *string   `json:"printedTypeLine,omitempty"`
+	Rarity          MtgRarity `json:"rarity"`
+	ReleasedAt      string    `json:"releasedAt"`
+	Reprint         bool      `json:"reprint"`
+	SetName         string    `json:"setName"`
+	SetType         string    `json:"setType"`
+	Set             string    `json:"set"`
+	SetID           string    `json:"setID"`
+	Variation       bool      `json:"variation"`
+	VariationOf     *string   `json:"variationOf,omitempty"`
+	// Finishes the printing is available in: nonfoil, foil, etched or glossy.
+	Finishes     []string `json:"finishes"`
+	FrameEffects []string `json:"frameEffects,omitempty"`
+	PromoTypes   []string `json:"promoTypes,omitempty"`
+	// Border color: black, white, borderless, yellow, silver or gold.
+	BorderColor string `json:"borderColor"`
+	// Frame edition, such as 1993, 1997, 2003, 2015 or future.
+	Frame     string  `json:"frame"`
+	Watermark *string `json:"watermark,omitempty"`
+	// Security stamp, if any: oval, triangle, acorn, circle, arena or heart.
+	SecurityStamp *string `json:"securityStamp,omitempty"`
+	// True if the printing has no rules text in its text box.
+	Textless bool `json:"textless"`
+	// True if the printing is a promotional print.
+	Promo bool `json:"promo"`
+	// True if the printing was only released in a video game.
+	Digital bool `json:"digital"`
+	// Catalog IDs of this printing on MTG Arena, Magic Online, TCGplayer and Cardmarket, when it has one.
+	ArenaID      *int `json:"arenaID,omitempty"`
+	MtgoID       *int `json:"mtgoID,omitempty"`
+	TcgplayerID  *int `json:"tcgplayerID,omitempty"`
+	CardmarketID *int `json:"cardmarketID,omitempty"`
+	// Latest market prices recorded from Scryfall, null when Scryfall listed none.
+	Prices *MtgPrices `json:"prices,omitempty"`
+	// Daily price snapshots of the last days (30 by default), oldest first.
+	PriceHistory []*MtgPricePoint `json:"priceHistory"`
 }
 
 // Minimal card version data for dashboard UI.
@@ -135,6 +188,40 @@ type MtgCardDashboard struct {
 	TagAssignments []*MtgTagAssignment        `json:"tagAssignments"`
 }
 
+// What one import changed about a card. A card new to the catalog is only marked as
+// added; its printings are not listed as new versions.
+type MtgCatalogChange struct {
+	ID     string `json:"_key"`
+	CardID string `json:"cardID"`
+	Name   string `json:"name"`
+	// When the import recorded the change (ISO timestamp). Changes of one import share it.
+	ImportedAt string `json:"importedAt"`
+	// Whether the card is new to the catalog, such as a freshly spoiled card.
+	Added bool `json:"added"`
+	// Oracle text, type line and mana cost changes.
+	FieldChanges []*MtgCatalogFieldChange `json:"fieldChanges"`
+	// Printings added to the card.
+	NewVersions []*MtgCatalogNewVersion `json:"newVersions"`
+}
+
+// A tracked Oracle field whose value changed in an import (errata).
+type MtgCatalogFieldChange struct {
+	Field MtgCatalogField `json:"field"`
+	// Value before the import, null when the card had none.
+	Before *string `json:"before,omitempty"`
+	// Value after the import, null when the card has none.
+	After *string `json:"after,omitempty"`
+}
+
+// A printing that appeared on an existing card in an import.
+type MtgCatalogNewVersion struct {
+	ID              string `json:"ID"`
+	Set             string `json:"set"`
+	SetName         string `json:"setName"`
+	CollectorNumber string `json:"collectorNumber"`
+	ReleasedAt      string `json:"releasedAt"`
+}
+
 // Input to create a new deck.
 type MtgCreateDeckInput struct {
 	Name string   `json:"name"`
@@ -150,6 +237,12 @@ type MtgCreateFilterPresetInput struct {
 	Page        int                   `json:"page"`
 }
 
+// Input payload to create a printing profile.
+type MtgCreatePrintingProfileInput struct {
+	Name  string                  `json:"name"`
+	Rules []*MtgPrintingRuleInput `json:"rules"`
+}
+
 // Input to create a new tag.
 type MtgCreateTagInput struct {
 	Name string `json:"name"`
@@ -167,6 +260,8 @@ type MtgDeck struct {
 	IgnoredCards   []string               `json:"ignoredCards"`
 	Tags           []*MtgTag              `json:"tags"`
 	Autosave       bool                   `json:"autosave"`
+	// Printing profile deciding the default versions of this deck's cards; null uses the global profile.
+	PrintingProfileID *string `json:"printingProfileID,omitempty"`
 }
 
 // A card entry in a deck with selection and positioning metadata.
@@ -209,6 +304,19 @@ type MtgDeckDashboard struct {
 	CardFrontImage *MtgDeckCardFrontImage  `json:"cardFrontImage,omitempty"`
 	Cards          []*MtgDeckCardDashboard `json:"cards"`
 	Tags           []*MtgTag               `json:"tags"`
+	// When the deck was last saved (ISO timestamp). Decks saved before this was tracked get the
+	// time the server first started tracking it.
+	SavedAt *string `json:"savedAt,omitempty"`
+	// Legality changes of the deck's cards recorded since the deck was last saved, limited to
+	// the deck's format when its type names one.
+	LegalityChanges []*MtgLegalityChange `json:"legalityChanges"`
+}
+
+// A token or emblem a deck can create, with the deck's cards that create it.
+type MtgDeckToken struct {
+	Token *MtgCard `json:"token"`
+	// Names of the cards in the deck that create the token, sorted.
+	CreatedBy []string `json:"createdBy"`
 }
 
 // Selected front image for a deck, referencing a card version.
@@ -228,6 +336,11 @@ type MtgDeleteFilterPresetInput struct {
 	PresetID string `json:"presetID"`
 }
 
+// Identifier wrapper for deleting a printing profile.
+type MtgDeletePrintingProfileInput struct {
+	ProfileID string `json:"profileID"`
+}
+
 // Input to delete a tag by ID.
 type MtgDeleteTagInput struct {
 	TagID string `json:"tagID"`
@@ -244,6 +357,12 @@ type MtgFilterPreset struct {
 	Page        int                   `json:"page"`
 }
 
+// Border color filter entry with ternary state.
+type MtgFilterBorderColorInput struct {
+	BorderColor string         `json:"borderColor"`
+	Value       TernaryBoolean `json:"value"`
+}
+
 // Card type filter entry with ternary state.
 type MtgFilterCardTypeInput struct {
 	CardType string         `json:"cardType"`
@@ -275,6 +394,13 @@ type MtgFilterEntries struct {
 	Expansions []*MtgFilterExpansion `json:"expansions"`
 	Legality   *MtgFilterLegality    `json:"legality"`
 	Layouts    []MtgLayout           `json:"layouts"`
+	// Distinct printing languages present in the catalog.
+	Languages []string `json:"languages"`
+	// Distinct printing border colors, frames, watermarks and security stamps present in the catalog.
+	BorderColors   []string `json:"borderColors"`
+	Frames         []string `json:"frames"`
+	Watermarks     []string `json:"watermarks"`
+	SecurityStamps []string `json:"securityStamps"`
 }
 
 // Expansion metadata used by filters and sorting.
@@ -287,12 +413,32 @@ type MtgFilterExpansion struct {
 	Games      []MtgGame `json:"games"`
 }
 
+// Catalog IDs of a printing. Cards match when one of their printings has every ID given.
+type MtgFilterExternalIDInput struct {
+	ArenaID      *int `json:"arenaID,omitempty"`
+	MtgoID       *int `json:"mtgoID,omitempty"`
+	TcgplayerID  *int `json:"tcgplayerID,omitempty"`
+	CardmarketID *int `json:"cardmarketID,omitempty"`
+}
+
+// Frame filter entry with ternary state.
+type MtgFilterFrameInput struct {
+	Frame string         `json:"frame"`
+	Value TernaryBoolean `json:"value"`
+}
+
 // Game platform filter entry with ternary state.
 type MtgFilterGameInput struct {
 	Game  MtgGame        `json:"game"`
 	Value TernaryBoolean `json:"value"`
 }
 
+// Printing language filter entry with ternary state. Languages use Scryfall codes (en, es, ja...).
+type MtgFilterLanguageInput struct {
+	Lang  string         `json:"lang"`
+	Value TernaryBoolean `json:"value"`
+}
+
 // Layout filter entry with ternary state.
 type MtgFilterLayoutInput struct {
 	Layout MtgLayout      `json:"layout"`
@@ -329,6 +475,20 @@ type MtgFilterPaginationInput struct {
 	PageSize int `json:"pageSize"`
 }
 
+// Price range in one currency. Cards match when their cheapest printing costs between min and
+// max (both inclusive and optional); cards without a price in the currency never match.
+type MtgFilterPriceInput struct {
+	Currency MtgPriceCurrency `json:"currency"`
+	Min      *float64         `json:"min,omitempty"`
+	Max      *float64         `json:"max,omitempty"`
+}
+
+// Integer range. Both bounds are inclusive and optional.
+type MtgFilterRangeInput struct {
+	Min *int `json:"min,omitempty"`
+	Max *int `json:"max,omitempty"`
+}
+
 // Rarity filter entry with ternary state.
 type MtgFilterRarityInput struct {
 	Rarity MtgRarity      `json:"rarity"`
@@ -365,6 +525,40 @@ type MtgFilterSearchInput struct {
 	Tags []*MtgFilterTagInput `json:"tags"`
 	// Filter by exact chain sequences.
 	Chains []*MtgFilterChainInput `json:"chains,omitempty"`
+	// Filter by printing language (TRUE = must have a printing in it, FALSE = ignore printings in it).
+	Languages []*MtgFilterLanguageInput `json:"languages,omitempty"`
+	// Filter by the price of the cheapest printing (any finish).
+	Price *MtgFilterPriceInput `json:"price,omitempty"`
+	// Game Changer list membership (TRUE = only Game Changers, FALSE = no Game Changers).
+	GameChanger *TernaryBoolean `json:"gameChanger,omitempty"`
+	// Reserved List membership (TRUE = only reserved cards, FALSE = no reserved cards).
+	Reserved *TernaryBoolean `json:"reserved,omitempty"`
+	// Defense range of battles; cards without a numeric defense never match.
+	Defense *MtgFilterRangeInput `json:"defense,omitempty"`
+	// Penny Dreadful rank range; unranked cards never match.
+	PennyRank *MtgFilterRangeInput `json:"pennyRank,omitempty"`
+	// Filter by printing border color (TRUE = must have a printing with it, FALSE = ignore printings with it).
+	BorderColors []*MtgFilterBorderColorInput `json:"borderColors,omitempty"`
+	// Filter by printing frame (TRUE = must have a printing with it, FALSE = ignore printings with it).
+	Frames []*MtgFilterFrameInput `json:"frames,omitempty"`
+	// Filter by printing watermark (TRUE = must have a printing with it, FALSE = ignore printings with it).
+	Watermarks []*MtgFilterWatermarkInput `json:"watermarks,omitempty"`
+	// Filter by printing security stamp (TRUE = must have a printing with it, FALSE = ignore printings with it).
+	SecurityStamps []*MtgFilterSecurityStampInput `json:"securityStamps,omitempty"`
+	// Promotional printings (TRUE = must have a promo printing, FALSE = must have a non-promo printing).
+	Promo *TernaryBoolean `json:"promo,omitempty"`
+	// Digital-only printings (TRUE = must have a digital printing, FALSE = must have a non-digital printing).
+	Digital *TernaryBoolean `json:"digital,omitempty"`
+	// Textless printings (TRUE = must have a textless printing, FALSE = must have a printing with text).
+	Textless *TernaryBoolean `json:"textless,omitempty"`
+	// Look a card up by the catalog IDs of one of its printings.
+	ExternalIDs *MtgFilterExternalIDInput `json:"externalIDs,omitempty"`
+}
+
+// Security stamp filter entry with ternary state.
+type MtgFilterSecurityStampInput struct {
+	SecurityStamp string         `json:"securityStamp"`
+	Value         TernaryBoolean `json:"value"`
 }
 
 // Set filter entry with ternary state.
@@ -400,6 +594,12 @@ type MtgFilterTagInput struct {
 	Value TernaryBoolean `json:"value"`
 }
 
+// Watermark filter entry with ternary state.
+type MtgFilterWatermarkInput struct {
+	Watermark string         `json:"watermark"`
+	Value     TernaryBoolean `json:"value"`
+}
+
 // Image URLs in multiple sizes from Scryfall.
 type MtgImage struct {
 	ArtCrop    string `json:"artCrop"`
@@ -410,6 +610,90 @@ type MtgImage struct {
 	Small      string `json:"small"`
 }
 
+// Running totals of an import. Counters of steps that have not started yet are 0.
+type MtgImportCounters struct {
+	// Number of sets stored.
+	SetsProcessed int `json:"setsProcessed"`
+	// Bytes of the bulk card file downloaded so far (Float because the file can exceed 2 GB).
+	BytesDownloaded float64 `json:"bytesDownloaded"`
+	// Total size of the bulk card file in bytes, 0 when unknown.
+	BytesTotal float64 `json:"bytesTotal"`
+	// Number of original card records upserted.
+	CardsProcessed int `json:"cardsProcessed"`
+	// Number of original card batches upserted.
+	BatchesUpserted int `json:"batchesUpserted"`
+	// Number of card groups built so far.
+	GroupsProcessed int `json:"groupsProcessed"`
+	// Total number of card groups to build.
+	GroupsTotal int `json:"groupsTotal"`
+	// Number of cards inserted into the catalog.
+	CardsInserted int `json:"cardsInserted"`
+	// Number of catalog cards whose content changed.
+	CardsUpdated int `json:"cardsUpdated"`
+	// Number of cards removed from the catalog.
+	CardsRemoved int `json:"cardsRemoved"`
+	// Number of original printings pruned because the import no longer saw them.
+	OriginalsPruned int `json:"originalsPruned"`
+	// Number of malformed records set aside in quarantine.
+	RecordsQuarantined int `json:"recordsQuarantined"`
+}
+
+// Timing of one phase of an import run.
+type MtgImportPhaseTiming struct {
+	Phase MtgImportPhase `json:"phase"`
+	// When the phase started (ISO timestamp).
+	StartedAt  string `json:"startedAt"`
+	DurationMs int    `json:"durationMs"`
+}
+
+// A finished import run, as recorded in the import history.
+type MtgImportRun struct {
+	ID      string           `json:"ID"`
+	Trigger MtgImportTrigger `json:"trigger"`
+	// COMPLETE, FAILED or CANCELLED.
+	Status MtgImportPhase `json:"status"`
+	// When the run started (ISO timestamp).
+	StartedAt string `json:"startedAt"`
+	// When the run finished (ISO timestamp).
+	FinishedAt string `json:"finishedAt"`
+	DurationMs int    `json:"durationMs"`
+	// Error message if the run failed.
+	Error *string `json:"error,omitempty"`
+	// Phase that was running when the run failed.
+	FailedPhase *MtgImportPhase `json:"failedPhase,omitempty"`
+	// Phases in the order they ran.
+	Phases []*MtgImportPhaseTiming `json:"phases"`
+	// Changes to mtg_sets. Sets are never removed.
+	Sets *MtgImportRunCounts `json:"sets"`
+	// Changes to mtg_cards.
+	Cards *MtgImportRunCounts `json:"cards"`
+	// Original printings archived or deleted because the bulk file no longer had them.
+	OriginalsPruned int `json:"originalsPruned"`
+	// Malformed records set aside in quarantine instead of stopping the run.
+	RecordsQuarantined int `json:"recordsQuarantined"`
+}
+
+// How an import run changed a collection.
+type MtgImportRunCounts struct {
+	Added     int `json:"added"`
+	Updated   int `json:"updated"`
+	Removed   int `json:"removed"`
+	Unchanged int `json:"unchanged"`
+}
+
+// Local files to import from instead of downloading from Scryfall. Files are
+// names relative to the server's import.importDir; absolute paths and names
+// leaving that directory are rejected. Omitted fields fall back to the server's
+// import settings.
+type MtgImportSourceInput struct {
+	// Name of a Scryfall sets file (a JSON array of sets or a saved /sets response).
+	SetsFile *string `json:"setsFile,omitempty"`
+	// Name of a Scryfall bulk cards file, such as default_cards.json.
+	CardsFile *string `json:"cardsFile,omitempty"`
+	// Name of a Scryfall rulings bulk file (rulings.json).
+	RulingsFile *string `json:"rulingsFile,omitempty"`
+}
+
 // Status response for import operations.
 type MtgImportStatus struct {
 	// Whether the import was successfully started (only relevant for mutation response).
@@ -422,12 +706,132 @@ type MtgImportStatus struct {
 	Phase MtgImportPhase `json:"phase"`
 	// Progress percentage (0-100).
 	Progress int `json:"progress"`
+	// Running totals of the current or last import.
+	Counters *MtgImportCounters `json:"counters"`
+	// Estimated seconds until the current step finishes, null when unknown.
+	EtaSeconds *int `json:"etaSeconds,omitempty"`
 	// When the import started (ISO timestamp).
 	StartedAt *string `json:"startedAt,omitempty"`
 	// When the import completed (ISO timestamp).
 	CompletedAt *string `json:"completedAt,omitempty"`
-	// Error message if the import failed.
+	// Error message if the import failed, prefixed with the phase that failed.
 	Error *string `json:"error,omitempty"`
+	// Phase that was running when the import failed, null unless phase is FAILED.
+	FailedPhase *MtgImportPhase `json:"failedPhase,omitempty"`
+	// When the scheduler starts the next import (ISO timestamp), null when scheduled imports are off.
+	NextScheduledRun *string `json:"nextScheduledRun,omitempty"`
+}
+
+// A change of a card's legality in one format, recorded by an import (a ban, unban,
+// restriction or rotation).
+type MtgLegalityChange struct {
+	ID     string `json:"_key"`
+	CardID string `json:"cardID"`
+	Name   string `json:"name"`
+	// Scryfall format key, such as standard or commander.
+	Format string `json:"format"`
+	// legal, not_legal, restricted or banned before the import; null when the format was not listed.
+	Before *string `json:"before,omitempty"`
+	// legal, not_legal, restricted or banned after the import; null when the format is no longer listed.
+	After *string `json:"after,omitempty"`
+	// When the import recorded the change (ISO timestamp).
+	ChangedAt string `json:"changedAt"`
+}
+
+// Pin a card to one of its versions within a printing profile.
+type MtgPinCardVersionInput struct {
+	ProfileID string `json:"profileID"`
+	CardID    string `json:"cardID"`
+	VersionID string `json:"versionID"`
+}
+
+// Prices of a card version on one day.
+type MtgPricePoint struct {
+	// Snapshot date (YYYY-MM-DD, UTC).
+	Date   string     `json:"date"`
+	Prices *MtgPrices `json:"prices"`
+}
+
+// Market prices of a card version as published by Scryfall. Prices are null when
+// Scryfall has none for that finish or currency.
+type MtgPrices struct {
+	Usd       *float64 `json:"usd,omitempty"`
+	UsdFoil   *float64 `json:"usdFoil,omitempty"`
+	UsdEtched *float64 `json:"usdEtched,omitempty"`
+	Eur       *float64 `json:"eur,omitempty"`
+	EurFoil   *float64 `json:"eurFoil,omitempty"`
+	EurEtched *float64 `json:"eurEtched,omitempty"`
+	Tix       *float64 `json:"tix,omitempty"`
+}
+
+// A card whose default printing is fixed to one version.
+type MtgPrintingPin struct {
+	CardID    string `json:"cardID"`
+	VersionID string `json:"versionID"`
+}
+
+// A named set of printing preferences. The rules are applied in order: the first rule
+// that tells two printings apart decides which one is preferred, and the import's
+// built-in ordering breaks the remaining ties. Pins override the rules for single cards.
+type MtgPrintingProfile struct {
+	ID    string             `json:"_key"`
+	Name  string             `json:"name"`
+	Rules []*MtgPrintingRule `json:"rules"`
+	Pins  []*MtgPrintingPin  `json:"pins"`
+	// True for the profile applied wherever no deck profile is chosen.
+	IsGlobal bool `json:"isGlobal"`
+}
+
+// One printing preference. Printings matching the rule are preferred, or avoided when
+// avoid is set; value is ignored for OLDEST and NEWEST.
+type MtgPrintingRule struct {
+	Kind  MtgPrintingRuleKind `json:"kind"`
+	Value *string             `json:"value,omitempty"`
+	Avoid bool                `json:"avoid"`
+}
+
+// A printing preference rule. value is required for every kind except OLDEST and NEWEST.
+type MtgPrintingRuleInput struct {
+	Kind  MtgPrintingRuleKind `json:"kind"`
+	Value *string             `json:"value,omitempty"`
+	Avoid *bool               `json:"avoid,omitempty"`
+}
+
+// A malformed upstream record an import set aside instead of aborting.
+type MtgQuarantinedRecord struct {
+	ID string `json:"_key"`
+	// Import run that last rejected the record.
+	RunID string            `json:"runID"`
+	Kind  MtgQuarantineKind `json:"kind"`
+	// Scryfall ID or set code of the record, when it could be read.
+	RecordID *string `json:"recordID,omitempty"`
+	// The record as received, as JSON.
+	Raw string `json:"raw"`
+	// Why the record was rejected.
+	Error string `json:"error"`
+	// When the record was quarantined (ISO timestamp).
+	QuarantinedAt string `json:"quarantinedAt"`
+}
+
+// A card related to another one, with the role it plays in that relationship.
+type MtgRelatedCard struct {
+	Component MtgRelatedCardComponent `json:"component"`
+	Card      *MtgCard                `json:"card"`
+}
+
+// A ruling or note on a card, as published in Scryfall's rulings bulk file.
+type MtgRuling struct {
+	// Who published it: wotc or scryfall.
+	Source string `json:"source"`
+	// Publication date (YYYY-MM-DD).
+	PublishedAt string `json:"publishedAt"`
+	Comment     string `json:"comment"`
+}
+
+// Choose the printing profile of a deck; a null profileID falls back to the global profile.
+type MtgSetDeckPrintingProfileInput struct {
+	DeckID    string  `json:"deckID"`
+	ProfileID *string `json:"profileID,omitempty"`
 }
 
 // A tag that can be assigned to cards and decks.
@@ -444,6 +848,12 @@ type MtgTagAssignment struct {
 	ChainDisplay string    `json:"chainDisplay"`
 }
 
+// Page of tokens and emblems matching a token search, with the total count for pagination.
+type MtgTokenSearch struct {
+	PagedTokens []*MtgCard `json:"pagedTokens"`
+	TotalCount  int        `json:"totalCount"`
+}
+
 // Unassign a tag from a card.
 type MtgUnassignTagFromCardInput struct {
 	TagID  string   `json:"tagID"`
@@ -457,6 +867,12 @@ type MtgUnassignTagFromDeckInput struct {
 	DeckID string `json:"deckID"`
 }
 
+// Remove a card's pin from a printing profile.
+type MtgUnpinCardVersionInput struct {
+	ProfileID string `json:"profileID"`
+	CardID    string `json:"cardID"`
+}
+
 // Input to update deck fields, cards, zones and front image.
 type MtgUpdateDeckInput struct {
 	DeckID         string                      `json:"deckID"`
@@ -477,6 +893,13 @@ type MtgUpdateFilterPresetInput struct {
 	Page        *int                  `json:"page,omitempty"`
 }
 
+// Fields allowed when updating a printing profile. rules replaces the whole list.
+type MtgUpdatePrintingProfileInput struct {
+	ProfileID string                  `json:"profileID"`
+	Name      *string                 `json:"name,omitempty"`
+	Rules     []*MtgPrintingRuleInput `json:"rules,omitempty"`
+}
+
 // Input to update an existing tag.
 type MtgUpdateTagInput struct {
 	TagID string  `json:"tagID"`
@@ -576,6 +999,50 @@ func (e DeckType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+// Oracle field of a card tracked by the catalog change feed.
+type MtgCatalogField string
+
+const (
+	MtgCatalogFieldOracleText MtgCatalogField = "ORACLE_TEXT"
+	MtgCatalogFieldTypeLine   MtgCatalogField = "TYPE_LINE"
+	MtgCatalogFieldManaCost   MtgCatalogField = "MANA_COST"
+)
+
+var AllMtgCatalogField = []MtgCatalogField{
+	MtgCatalogFieldOracleText,
+	MtgCatalogFieldTypeLine,
+	MtgCatalogFieldManaCost,
+}
+
+func (e MtgCatalogField) IsValid() bool {
+	switch e {
+	case MtgCatalogFieldOracleText, MtgCatalogFieldTypeLine, MtgCatalogFieldManaCost:
+		return true
+	}
+	return false
+}
+
+func (e MtgCatalogField) String() string {
+	return string(e)
+}
+
+func (e *MtgCatalogField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MtgCatalogField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MTG_CatalogField", str)
+	}
+	return nil
+}
+
+func (e MtgCatalogField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 // Magic color identity abbreviations.
 type MtgColor string
 
@@ -679,6 +1146,8 @@ const (
 	MtgFilterSortByType       MtgFilterSortBy = "TYPE"
 	MtgFilterSortBySet        MtgFilterSortBy = "SET"
 	MtgFilterSortByReleasedAt MtgFilterSortBy = "RELEASED_AT"
+	// Cheapest printing, in the currency of the price filter (USD without one). Cards without a price sort last.
+	MtgFilterSortByPrice MtgFilterSortBy = "PRICE"
 )
 
 var AllMtgFilterSortBy = []MtgFilterSortBy{
@@ -689,11 +1158,12 @@ var AllMtgFilterSortBy = []MtgFilterSortBy{
 	MtgFilterSortByType,
 	MtgFilterSortBySet,
 	MtgFilterSortByReleasedAt,
+	MtgFilterSortByPrice,
 }
 
 func (e MtgFilterSortBy) IsValid() bool {
 	switch e {
-	case MtgFilterSortByName, MtgFilterSortByCmc, MtgFilterSortByRarity, MtgFilterSortByColor, MtgFilterSortByType, MtgFilterSortBySet, MtgFilterSortByReleasedAt:
+	case MtgFilterSortByName, MtgFilterSortByCmc, MtgFilterSortByRarity, MtgFilterSortByColor, MtgFilterSortByType, MtgFilterSortBySet, MtgFilterSortByReleasedAt, MtgFilterSortByPrice:
 		return true
 	}
 	return false
@@ -819,6 +1289,7 @@ const (
 	MtgImportPhaseRebuildingIndex MtgImportPhase = "REBUILDING_INDEX"
 	MtgImportPhaseComplete        MtgImportPhase = "COMPLETE"
 	MtgImportPhaseFailed          MtgImportPhase = "FAILED"
+	MtgImportPhaseCancelled       MtgImportPhase = "CANCELLED"
 )
 
 var AllMtgImportPhase = []MtgImportPhase{
@@ -831,11 +1302,12 @@ var AllMtgImportPhase = []MtgImportPhase{
 	MtgImportPhaseRebuildingIndex,
 	MtgImportPhaseComplete,
 	MtgImportPhaseFailed,
+	MtgImportPhaseCancelled,
 }
 
 func (e MtgImportPhase) IsValid() bool {
 	switch e {
-	case MtgImportPhaseIdle, MtgImportPhaseResettingTimers, MtgImportPhaseFetchingSets, MtgImportPhaseProcessingSets, MtgImportPhaseFetchingCards, MtgImportPhaseProcessingCards, MtgImportPhaseRebuildingIndex, MtgImportPhaseComplete, MtgImportPhaseFailed:
+	case MtgImportPhaseIdle, MtgImportPhaseResettingTimers, MtgImportPhaseFetchingSets, MtgImportPhaseProcessingSets, MtgImportPhaseFetchingCards, MtgImportPhaseProcessingCards, MtgImportPhaseRebuildingIndex, MtgImportPhaseComplete, MtgImportPhaseFailed, MtgImportPhaseCancelled:
 		return true
 	}
 	return false
@@ -862,6 +1334,50 @@ func (e MtgImportPhase) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+// What started an import run.
+type MtgImportTrigger string
+
+const (
+	// Started with the reimportMTGData mutation.
+	MtgImportTriggerManual MtgImportTrigger = "MANUAL"
+	// Started by the import scheduler.
+	MtgImportTriggerScheduled MtgImportTrigger = "SCHEDULED"
+)
+
+var AllMtgImportTrigger = []MtgImportTrigger{
+	MtgImportTriggerManual,
+	MtgImportTriggerScheduled,
+}
+
+func (e MtgImportTrigger) IsValid() bool {
+	switch e {
+	case MtgImportTriggerManual, MtgImportTriggerScheduled:
+		return true
+	}
+	return false
+}
+
+func (e MtgImportTrigger) String() string {
+	return string(e)
+}
+
+func (e *MtgImportTrigger) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MtgImportTrigger(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MTG_ImportTrigger", str)
+	}
+	return nil
+}
+
+func (e MtgImportTrigger) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 // Card layouts as defined by Scryfall.
 type MtgLayout string
 
@@ -948,6 +1464,172 @@ func (e MtgLayout) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+// Currencies Scryfall publishes prices in.
+type MtgPriceCurrency string
+
+const (
+	MtgPriceCurrencyUsd MtgPriceCurrency = "USD"
+	MtgPriceCurrencyEur MtgPriceCurrency = "EUR"
+	MtgPriceCurrencyTix MtgPriceCurrency = "TIX"
+)
+
+var AllMtgPriceCurrency = []MtgPriceCurrency{
+	MtgPriceCurrencyUsd,
+	MtgPriceCurrencyEur,
+	MtgPriceCurrencyTix,
+}
+
+func (e MtgPriceCurrency) IsValid() bool {
+	switch e {
+	case MtgPriceCurrencyUsd, MtgPriceCurrencyEur, MtgPriceCurrencyTix:
+		return true
+	}
+	return false
+}
+
+func (e MtgPriceCurrency) String() string {
+	return string(e)
+}
+
+func (e *MtgPriceCurrency) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MtgPriceCurrency(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MTG_PriceCurrency", str)
+	}
+	return nil
+}
+
+func (e MtgPriceCurrency) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// What a printing preference rule compares. OLDEST and NEWEST order by release date;
+// every other kind matches printings against the rule's value.
+type MtgPrintingRuleKind string
+
+const (
+	// Printings from the set with this code.
+	MtgPrintingRuleKindSet MtgPrintingRuleKind = "SET"
+	// Printings with this border color (black, white, borderless, yellow, silver, gold).
+	MtgPrintingRuleKindBorderColor MtgPrintingRuleKind = "BORDER_COLOR"
+	// Printings with this frame edition (1993, 1997, 2003, 2015, future).
+	MtgPrintingRuleKindFrame MtgPrintingRuleKind = "FRAME"
+	// Printings with this frame effect (showcase, extendedart, etched...).
+	MtgPrintingRuleKindFrameEffect MtgPrintingRuleKind = "FRAME_EFFECT"
+	// Printings available in this finish (nonfoil, foil, etched, glossy).
+	MtgPrintingRuleKindFinish MtgPrintingRuleKind = "FINISH"
+	// Printings with this promo type (prerelease, boosterfun...).
+	MtgPrintingRuleKindPromoType MtgPrintingRuleKind = "PROMO_TYPE"
+	// Printings illustrated by this artist.
+	MtgPrintingRuleKindArtist MtgPrintingRuleKind = "ARTIST"
+	// Printings in this language (Scryfall code).
+	MtgPrintingRuleKindLanguage MtgPrintingRuleKind = "LANGUAGE"
+	// Printings available in this game (paper, mtgo, arena).
+	MtgPrintingRuleKindGame MtgPrintingRuleKind = "GAME"
+	// The earliest released printing.
+	MtgPrintingRuleKindOldest MtgPrintingRuleKind = "OLDEST"
+	// The most recently released printing.
+	MtgPrintingRuleKindNewest MtgPrintingRuleKind = "NEWEST"
+)
+
+var AllMtgPrintingRuleKind = []MtgPrintingRuleKind{
+	MtgPrintingRuleKindSet,
+	MtgPrintingRuleKindBorderColor,
+	MtgPrintingRuleKindFrame,
+	MtgPrintingRuleKindFrameEffect,
+	MtgPrintingRuleKindFinish,
+	MtgPrintingRuleKindPromoType,
+	MtgPrintingRuleKindArtist,
+	MtgPrintingRuleKindLanguage,
+	MtgPrintingRuleKindGame,
+	MtgPrintingRuleKindOldest,
+	MtgPrintingRuleKindNewest,
+}
+
+func (e MtgPrintingRuleKind) IsValid() bool {
+	switch e {
+	case MtgPrintingRuleKindSet, MtgPrintingRuleKindBorderColor, MtgPrintingRuleKindFrame, MtgPrintingRuleKindFrameEffect, MtgPrintingRuleKindFinish, MtgPrintingRuleKindPromoType, MtgPrintingRuleKindArtist, MtgPrintingRuleKindLanguage, MtgPrintingRuleKindGame, MtgPrintingRuleKindOldest, MtgPrintingRuleKindNewest:
+		return true
+	}
+	return false
+}
+
+func (e MtgPrintingRuleKind) String() string {
+	return string(e)
+}
+
+func (e *MtgPrintingRuleKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MtgPrintingRuleKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MTG_PrintingRuleKind", str)
+	}
+	return nil
+}
+
+func (e MtgPrintingRuleKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Import step that rejected a quarantined record.
+type MtgQuarantineKind string
+
+const (
+	// A Scryfall set that failed to decode or validate.
+	MtgQuarantineKindSet MtgQuarantineKind = "SET"
+	// A Scryfall card of the bulk file that failed to decode or validate.
+	MtgQuarantineKindCard MtgQuarantineKind = "CARD"
+	// A stored original card that could not be grouped into the catalog.
+	MtgQuarantineKindCardGroup MtgQuarantineKind = "CARD_GROUP"
+	// An MTGJSON set, with its cards, that failed to decode or validate.
+	MtgQuarantineKindMtgjsonSet MtgQuarantineKind = "MTGJSON_SET"
+)
+
+var AllMtgQuarantineKind = []MtgQuarantineKind{
+	MtgQuarantineKindSet,
+	MtgQuarantineKindCard,
+	MtgQuarantineKindCardGroup,
+	MtgQuarantineKindMtgjsonSet,
+}
+
+func (e MtgQuarantineKind) IsValid() bool {
+	switch e {
+	case MtgQuarantineKindSet, MtgQuarantineKindCard, MtgQuarantineKindCardGroup, MtgQuarantineKindMtgjsonSet:
+		return true
+	}
+	return false
+}
+
+func (e MtgQuarantineKind) String() string {
+	return string(e)
+}
+
+func (e *MtgQuarantineKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MtgQuarantineKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MTG_QuarantineKind", str)
+	}
+	return nil
+}
+
+func (e MtgQuarantineKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 // Rarity tiers for a printing.
 type MtgRarity string
 
@@ -994,6 +1676,52 @@ func (e MtgRarity) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+// Role a related card plays in a relationship listed in Scryfall's all_parts.
+type MtgRelatedCardComponent string
+
+const (
+	MtgRelatedCardComponentToken      MtgRelatedCardComponent = "token"
+	MtgRelatedCardComponentMeldPart   MtgRelatedCardComponent = "meld_part"
+	MtgRelatedCardComponentMeldResult MtgRelatedCardComponent = "meld_result"
+	MtgRelatedCardComponentComboPiece MtgRelatedCardComponent = "combo_piece"
+)
+
+var AllMtgRelatedCardComponent = []MtgRelatedCardComponent{
+	MtgRelatedCardComponentToken,
+	MtgRelatedCardComponentMeldPart,
+	MtgRelatedCardComponentMeldResult,
+	MtgRelatedCardComponentComboPiece,
+}
+
+func (e MtgRelatedCardComponent) IsValid() bool {
+	switch e {
+	case MtgRelatedCardComponentToken, MtgRelatedCardComponentMeldPart, MtgRelatedCardComponentMeldResult, MtgRelatedCardComponentComboPiece:
+		return true
+	}
+	return false
+}
+
+func (e MtgRelatedCardComponent) String() string {
+	return string(e)
+}
+
+func (e *MtgRelatedCardComponent) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MtgRelatedCardComponent(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MTG_RelatedCardComponent", str)
+	}
+	return nil
+}
+
+func (e MtgRelatedCardComponent) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 // Three-state boolean used for filter entries.
 type TernaryBoolean string
 
@@ -33,34 +33,53 @@ type MTG_SetDB struct {
 // MTG_CardDB is the persisted aggregated card document used by the app.
 type MTG_CardDB struct {
 	ID             string               `json:"_key"`
+	OracleID       string               `json:"oracleID,omitempty"`
 	Layout         scryfallModel.Layout `json:"layout"`
 	CMC            float64              `json:"CMC"`
 	ColorIdentity  []string             `json:"colorIdentity"`
 	ColorIndicator *[]string            `json:"colorIndicator,omitempty"`
 	Colors         *[]string            `json:"colors,omitempty"`
+	Defense        *string              `json:"defense,omitempty"`
 	EDHRecRank     *int                 `json:"EDHRecRank,omitempty"`
+	GameChanger    bool                 `json:"gameChanger"`
 	Keywords       []string             `json:"keywords"`
 	Loyalty        *string              `json:"loyalty,omitempty"`
 	ManaCost       *string              `json:"manaCost,omitempty"`
 	Name           string               `json:"name"`
 	OracleText     *string              `json:"oracleText,omitempty"`
+	PennyRank      *int                 `json:"pennyRank,omitempty"`
 	Power          *string              `json:"power,omitempty"`
 	ProducedMana   *[]string            `json:"producedMana,omitempty"`
+	Reserved       bool                 `json:"reserved"`
 	Toughness      *string              `json:"toughness,omitempty"`
 	TypeLine       string               `json:"typeLine"`
 	Versions       []MTG_CardVersionDB  `json:"versions"`
+	ContentHash    string               `json:"contentHash,omitempty"`
+}
+
+// MTG_CardKeyAliasDB maps a retired card key (such as a name-based key from
+// before cards were grouped by oracle ID) to the current key in mtg_cards.
+type MTG_CardKeyAliasDB struct {
+	ID      string `json:"_key"`
+	CardKey string `json:"cardKey"`
 }
 
 // MTG_CardVersionDB describes a specific printing/version of a card.
 type MTG_CardVersionDB struct {
 	ID              string                     `json:"ID"`
+	ArenaID         *int                       `json:"arenaID,omitempty"`
 	Artist          *string                    `json:"artist,omitempty"`
 	Booster         bool                       `json:"booster"`
+	BorderColor     string                     `json:"borderColor"`
 	CardFaces       *[]MTG_CardVersionFaceDB   `json:"cardFaces,omitempty"`
+	CardmarketID    *int                       `json:"cardmarketID,omitempty"`
 	CollectorNumber string                     `json:"collectorNumber"`
+	DefaultRank     int                        `json:"defaultRank"`
+	Digital         bool                       `json:"digital"`
 	Finishes        []string                   `json:"finishes"`
 	FlavorName      *string                    `json:"flavorName,omitempty"`
 	FlavorText      *string                    `json:"flavorText,omitempty"`
+	Frame           string                     `json:"frame"`
 	FrameEffects    *[]string                  `json:"frameEffects,omitempty"`
 	FullArt         bool                       `json:"fullArt"`
 	Games           []scryfallModel.Game       `json:"games"`
@@ -69,35 +88,51 @@ type MTG_CardVersionDB struct {
 	IsDefault       bool                       `json:"isDefault"`
 	Lang            scryfallModel.CardLanguage `json:"lang"`
 	Legalities      map[string]string          `json:"legalities"`
+	MtgoID          *int                       `json:"mtgoID,omitempty"`
 	Name            string                     `json:"name"`
 	PrintedName     string                     `json:"printedName"`
+	PrintedText     *string                    `json:"printedText,omitempty"`
+	PrintedTypeLine *string                    `json:"printedTypeLine,omitempty"`
+	Promo           bool                       `json:"promo"`
 	PromoTypes      *[]string                  `json:"promoTypes,omitempty"`
 	Rarity          scryfallModel.Rarity       `json:"rarity"`
 	ReleasedAt      string                     `json:"releasedAt"`
 	Reprint         bool                       `json:"reprint"`
+	SecurityStamp   *string                    `json:"securityStamp,omitempty"`
 	Set             string                     `json:"set"`
 	SetID           string                     `json:"setID"`
 	SetName         string                     `json:"setName"`
 	SetType         string                     `json:"setType"`
+	TcgplayerID     *int                       `json:"tcgplayerID,omitempty"`
+	Textless        bool                       `json:"textless"`
 	Variation       bool                       `json:"variation"`
 	VariationOf     *string                    `json:"variationOf,omitempty"`
+	Watermark       *string                    `json:"watermark,omitempty"`
 	IllustrationID  *string                    `json:"illustrationID,omitempty"`
+	// Prices are only carried through the import; they are stored in
+	// mtg_card_prices so daily price moves do not rewrite the catalog.
+	Prices *model.MtgPrices `json:"-"`
 }
 
 // MTG_CardVersionFaceDB describes a face of a multi-faced card version.
 type MTG_CardVersionFaceDB struct {
-	Artist         *string         `json:"artist,omitempty"`
-	CMC            *float64        `json:"CMC,omitempty"`
-	ColorIndicator *[]string       `json:"colorIndicator,omitempty"`
-	Colors         *[]string       `json:"colors,omitempty"`
-	FlavorText     *string         `json:"flavorText,omitempty"`
-	ImageUris      *model.MtgImage `json:"imageUris,omitempty"`
-	Layout         *string         `json:"layout,omitempty"`
-	Loyalty        *string         `json:"loyalty,omitempty"`
-	ManaCost       string          `json:"manaCost"`
-	Name           string          `json:"name"`
-	OracleText     *string         `json:"oracleText,omitempty"`
-	Power          *string         `json:"power,omitempty"`
-	Toughness      *string         `json:"toughness,omitempty"`
-	TypeLine       *string         `json:"typeLine,omitempty"`
+	Artist          *string         `json:"artist,omitempty"`
+	CMC             *float64        `json:"CMC,omitempty"`
+	ColorIndicator  *[]string       `json:"colorIndicator,omitempty"`
+	Colors          *[]string       `json:"colors,omitempty"`
+	Defense         *string         `json:"defense,omitempty"`
+	FlavorText      *string         `json:"flavorText,omitempty"`
+	ImageUris       *model.MtgImage `json:"imageUris,omitempty"`
+	Layout          *string         `json:"layout,omitempty"`
+	Loyalty         *string         `json:"loyalty,omitempty"`
+	ManaCost        string          `json:"manaCost"`
+	Name            string          `json:"name"`
+	OracleText      *string         `json:"oracleText,omitempty"`
+	Power           *string         `json:"power,omitempty"`
+	PrintedName     *string         `json:"printedName,omitempty"`
+	PrintedText     *string         `json:"printedText,omitempty"`
+	PrintedTypeLine *string         `json:"printedTypeLine,omitempty"`
+	Toughness       *string         `json:"toughness,omitempty"`
+	TypeLine        *string         `json:"typeLine,omitempty"`
+	Watermark       *string         `json:"watermark,omitempty"`
 }
@@ -199,3 +199,12 @@ type Preview struct {
 	SourceURI   *string `json:"source_uri"`   // A link to the preview for this card.
 	Source      *string `json:"source"`       // The name of the source that previewed this card.
 }
+
+// Ruling mirrors one entry of Scryfall's rulings bulk file.
+type Ruling struct {
+	Object      string `json:"object"`       // A content type for this object, always ruling.
+	OracleID    string `json:"oracle_id"`    // The oracle ID of the card this ruling applies to.
+	Source      string `json:"source"`       // A computer-readable string indicating which company produced this ruling, either wotc or scryfall.
+	PublishedAt string `json:"published_at"` // The date when the ruling or note was published.
+	Comment     string `json:"comment"`      // The text of the ruling.
+}
@@ -6,6 +6,7 @@ import (
 	"magic-helper/graph/model"
 	"magic-helper/util"
 	"magic-helper/util/mtgCardSearch"
+	"sort"
 	"strings"
 	"time"
 
@@ -42,7 +43,20 @@ func GetMTGCards(ctx context.Context) ([]*model.MtgCard, error) {
 					chainDisplay: CONCAT_SEPARATOR(" → ", allTagNames)
 				}
 			)
-			RETURN MERGE(doc, { tagAssignments: tagAssignments })
+			LET rulings = DOCUMENT("mtg_card_rulings", doc._key).rulings || []
+			// Current prices are the latest snapshot of each version.
+			LET versions = (
+				FOR version IN doc.versions
+					LET prices = FIRST(
+						FOR price IN mtg_card_prices
+							FILTER price.versionID == version.ID
+							SORT price.date DESC
+							LIMIT 1
+							RETURN price.prices
+					)
+					RETURN MERGE(version, { prices: prices })
+			)
+			RETURN MERGE(doc, { tagAssignments: tagAssignments, rulings: rulings, versions: versions })
 	`)
 
 	// Build the query
@@ -87,7 +101,7 @@ func GetMTGCards(ctx context.Context) ([]*model.MtgCard, error) {
 	return cards, nil
 }
 
-// GetMTGFilters returns filter entries (types, layouts, expansions, legalities).
+// GetMTGFilters returns filter entries (types, layouts, languages, expansions, legalities).
 // Uses in-memory processing when index is warm; otherwise queries ArangoDB.
 func GetMTGFilters(ctx context.Context) (*model.MtgFilterEntries, error) {
 	log.Info().Msg("GetMTGFilters: Started")
@@ -107,7 +121,11 @@ func GetMTGFilters(ctx context.Context) (*model.MtgFilterEntries, error) {
 			RETURN {
 				name: card.name,
 				typeLine: card.typeLine,
-				layout: card.layout
+				layout: card.layout,
+				versions: (
+					FOR v IN card.versions
+					RETURN { lang: v.lang, borderColor: v.borderColor, frame: v.frame, watermark: v.watermark, securityStamp: v.securityStamp }
+				)
 			}
 		`)
 
@@ -132,6 +150,11 @@ func GetMTGFilters(ctx context.Context) (*model.MtgFilterEntries, error) {
 	var typeMap = make(map[string]map[string]struct{}) // Map to store types and their subtypes
 	var gatheredTypes = make(map[string]struct{})      // Set to store all types
 	var layouts = make(map[string]struct{})            // Set to store all layouts
+	var languages = make(map[string]struct{})          // Set to store all printing languages
+	var borderColors = make(map[string]struct{})       // Set to store all printing border colors
+	var frames = make(map[string]struct{})             // Set to store all printing frames
+	var watermarks = make(map[string]struct{})         // Set to store all printing watermarks
+	var securityStamps = make(map[string]struct{})     // Set to store all printing security stamps
 
 	// Process cards from either index or database
 	for _, card := range cards {
@@ -160,6 +183,28 @@ func GetMTGFilters(ctx context.Context) (*model.MtgFilterEntries, error) {
 
 		// Add the layout to the layouts set
 		layouts[string(card.Layout)] = struct{}{}
+
+		// Add the printing languages to the languages set
+		for _, version := range card.Versions {
+			if version == nil {
+				continue
+			}
+			if version.Lang != "" {
+				languages[version.Lang] = struct{}{}
+			}
+			if version.BorderColor != "" {
+				borderColors[version.BorderColor] = struct{}{}
+			}
+			if version.Frame != "" {
+				frames[version.Frame] = struct{}{}
+			}
+			if version.Watermark != nil && *version.Watermark != "" {
+				watermarks[*version.Watermark] = struct{}{}
+			}
+			if version.SecurityStamp != nil && *version.SecurityStamp != "" {
+				securityStamps[*version.SecurityStamp] = struct{}{}
+			}
+		}
 	}
 
 	// Now perform the final cleaning to remove subtypes that exist in the gathered types
@@ -204,10 +249,31 @@ func GetMTGFilters(ctx context.Context) (*model.MtgFilterEntries, error) {
 		filterEntries.Layouts = append(filterEntries.Layouts, model.MtgLayout(layout))
 	}
 
+	filterEntries.Languages = make([]string, 0, len(languages))
+	for language := range languages {
+		filterEntries.Languages = append(filterEntries.Languages, language)
+	}
+	sort.Strings(filterEntries.Languages)
+
+	filterEntries.BorderColors = sortedKeys(borderColors)
+	filterEntries.Frames = sortedKeys(frames)
+	filterEntries.Watermarks = sortedKeys(watermarks)
+	filterEntries.SecurityStamps = sortedKeys(securityStamps)
+
 	log.Info().Msg("GetMTGFilters: Finished")
 	return &filterEntries, nil
 }
 
+// sortedKeys returns the keys of a string set in ascending order.
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // GetMTGExpansions fetches distinct expansions (set and setName).
 func GetMTGExpansions(ctx context.Context) ([]*model.MtgFilterExpansion, error) {
 	log.Info().Msg("GetMTGExpansions: Started")
@@ -523,6 +589,13 @@ func GetMTGCardsFiltered(ctx context.Context, filter model.MtgFilterSearchInput,
 			}
 		}
 	}
+	// Mark default versions by the deck's (or the global) printing profile. Runs after the
+	// games restriction so the chosen default is always one of the returned versions.
+	pagedCards, err = ApplyPrintingProfileForDeck(ctx, filter.DeckID, pagedCards)
+	if err != nil {
+		return nil, err
+	}
+
 	// Ensure tagAssignments slice is never nil (e.g. when cards come from index built without tags).
 	for _, card := range pagedCards {
 		if card.TagAssignments == nil {
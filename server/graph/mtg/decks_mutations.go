@@ -4,6 +4,7 @@ import (
 	"context"
 	"magic-helper/arango"
 	"magic-helper/graph/model"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -33,9 +34,10 @@ func CreateMTGDeck(ctx context.Context, input model.MtgCreateDeckInput) (*model.
 	}
 
 	deckDB := model.MTGDeckDB{
-		ID:   nil,
-		Name: input.Name,
-		Type: input.Type,
+		ID:      nil,
+		Name:    input.Name,
+		Type:    input.Type,
+		SavedAt: time.Now().UTC().Format(time.RFC3339),
 	}
 	meta, err := col.CreateDocument(ctx, deckDB)
 	if err != nil {
@@ -50,6 +52,39 @@ func CreateMTGDeck(ctx context.Context, input model.MtgCreateDeckInput) (*model.
 	}, nil
 }
 
+// BackfillMTGDeckSavedAt sets savedAt to now on decks saved before it was tracked,
+// so their legality changes are listed from now on instead of every change ever
+// recorded. Decks that have savedAt are left alone, so it is safe on every start.
+func BackfillMTGDeckSavedAt(ctx context.Context) error {
+	aq := arango.NewQuery( /* aql */ `
+		FOR doc IN mtg_decks
+			FILTER doc.savedAt == null OR doc.savedAt == ""
+			UPDATE doc WITH { savedAt: @savedAt } IN mtg_decks
+			COLLECT WITH COUNT INTO backfilled
+			RETURN backfilled
+	`)
+	aq.AddBindVar("savedAt", time.Now().UTC().Format(time.RFC3339))
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("BackfillMTGDeckSavedAt: Error updating decks")
+		return err
+	}
+	defer cursor.Close()
+
+	var backfilled int
+	if cursor.HasMore() {
+		if _, err := cursor.ReadDocument(ctx, &backfilled); err != nil {
+			log.Error().Err(err).Msg("BackfillMTGDeckSavedAt: Error reading count")
+			return err
+		}
+	}
+	if backfilled > 0 {
+		log.Info().Int("decks", backfilled).Msg("BackfillMTGDeckSavedAt: Backfilled savedAt")
+	}
+	return nil
+}
+
 // DeleteMTGDeck deletes a deck and associated edges.
 func DeleteMTGDeck(ctx context.Context, input model.MtgDeleteDeckInput) (*model.Response, error) {
 	log.Info().Msg("DeleteMTGDeck: Started")
@@ -119,6 +154,7 @@ func UpdateMTGDeck(ctx context.Context, input model.MtgUpdateDeckInput) (*model.
 			type: @type,
 			zones: @zones,
 			autosave: @autosave,
+			savedAt: @savedAt,
 		} IN mtg_decks
 		RETURN NEW
 	`)
@@ -131,6 +167,7 @@ func UpdateMTGDeck(ctx context.Context, input model.MtgUpdateDeckInput) (*model.
 	aq.AddBindVar("type", input.Type)
 	aq.AddBindVar("zones", input.Zones)
 	aq.AddBindVar("autosave", input.Autosave)
+	aq.AddBindVar("savedAt", time.Now().UTC().Format(time.RFC3339))
 
 	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
 	if err != nil {
@@ -8,6 +8,15 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// deckLegalityFormats maps deck types to the Scryfall format whose legality
+// changes the dashboard reports; other types report every format.
+var deckLegalityFormats = map[model.DeckType]string{
+	model.DeckTypeStandard:      "standard",
+	model.DeckTypeHistoric:      "historic",
+	model.DeckTypeStandardBrawl: "standardbrawl",
+	model.DeckTypeBrawl:         "brawl",
+}
+
 // GetMTGDecks lists all decks with a front image preview and sorted card edges.
 func GetMTGDecks(ctx context.Context) ([]*model.MtgDeckDashboard, error) {
 	log.Info().Msg("GetMTGADecks: Started")
@@ -46,9 +55,20 @@ func GetMTGDecks(ctx context.Context) ([]*model.MtgDeckDashboard, error) {
 				SORT tag.name ASC
 				RETURN { _key: tag._key, name: tag.name }
 			)
-		RETURN MERGE(doc, {cardFrontImage, cards, tags})
+			LET deckFormat = @deckFormats[doc.type]
+			LET legalityChanges = (
+				FOR change IN mtg_legality_changes
+					FILTER change.cardID IN cards[*].card._key
+					FILTER doc.savedAt != null AND change.changedAt > doc.savedAt
+					FILTER deckFormat == null OR change.format == deckFormat
+					SORT change.changedAt DESC, change.name ASC, change.format ASC
+					RETURN change
+			)
+		RETURN MERGE(doc, {cardFrontImage, cards, tags, legalityChanges})
 	`)
 
+	aq.AddBindVar("deckFormats", deckLegalityFormats)
+
 	log.Info().Str("query", aq.Query).Msg("GetMTGADecks: Querying database")
 
 	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
@@ -147,6 +167,19 @@ func GetMTGDeck(ctx context.Context, deckID string) (*model.MtgDeck, error) {
 		deck.Tags = []*model.MtgTag{}
 	}
 
+	// Mark default versions by the deck's (or the global) printing profile.
+	cards := make([]*model.MtgCard, len(deck.Cards))
+	for i, deckCard := range deck.Cards {
+		cards[i] = deckCard.Card
+	}
+	cards, err = ApplyPrintingProfileForDeck(ctx, &deck.ID, cards)
+	if err != nil {
+		return nil, err
+	}
+	for i, deckCard := range deck.Cards {
+		deckCard.Card = cards[i]
+	}
+
 	log.Info().Msg("GetMTGDeck: Finished")
 	return &deck, nil
 }
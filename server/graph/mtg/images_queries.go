@@ -0,0 +1,57 @@
+package mtg
+
+import (
+	"context"
+
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MTGCardVersionImages holds the image URIs of a card version: its own, and
+// those of its faces for multi-faced printings that have no version image.
+type MTGCardVersionImages struct {
+	ImageUris *model.MtgImage   `json:"imageUris"`
+	Faces     []*model.MtgImage `json:"faces"`
+}
+
+// GetMTGCardVersionImages returns the image URIs of the card version with the
+// given ID, or nil when no card has that version.
+func GetMTGCardVersionImages(ctx context.Context, versionID string) (*MTGCardVersionImages, error) {
+	log.Debug().Str("versionID", versionID).Msg("GetMTGCardVersionImages: Started")
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR card IN mtg_cards
+            FILTER @versionID IN card.versions[*].ID
+            FOR version IN card.versions
+                FILTER version.ID == @versionID
+                LIMIT 1
+                RETURN {
+                    imageUris: version.imageUris,
+                    faces: (version.cardFaces || [])[*].imageUris
+                }
+    `)
+
+	aq.AddBindVar("versionID", versionID)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGCardVersionImages: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	if !cursor.HasMore() {
+		return nil, nil
+	}
+
+	var images MTGCardVersionImages
+	if _, err := cursor.ReadDocument(ctx, &images); err != nil {
+		log.Error().Err(err).Msg("GetMTGCardVersionImages: Error reading document")
+		return nil, err
+	}
+
+	log.Debug().Str("versionID", versionID).Msg("GetMTGCardVersionImages: Finished")
+	return &images, nil
+}
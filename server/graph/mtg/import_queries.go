@@ -0,0 +1,189 @@
+package mtg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultImportHistoryLimit is the number of runs returned when no limit is given.
+const defaultImportHistoryLimit = 20
+
+// GetMTGImportHistory returns the most recent import runs, newest first.
+func GetMTGImportHistory(ctx context.Context, limit *int) ([]*model.MtgImportRun, error) {
+	log.Info().Msg("GetMTGImportHistory: Started")
+
+	count := defaultImportHistoryLimit
+	if limit != nil && *limit > 0 {
+		count = *limit
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR run IN mtg_import_runs
+            SORT run.startedAt DESC
+            LIMIT @limit
+            RETURN run
+    `)
+
+	aq.AddBindVar("limit", count)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGImportHistory: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	runs := []*model.MtgImportRun{}
+	for cursor.HasMore() {
+		var runDB model.MTGImportRunDB
+		_, err := cursor.ReadDocument(ctx, &runDB)
+		if err != nil {
+			log.Error().Err(err).Msg("GetMTGImportHistory: Error reading document")
+			return nil, err
+		}
+		runs = append(runs, runDB.ToModel())
+	}
+
+	log.Info().Msg("GetMTGImportHistory: Finished")
+	return runs, nil
+}
+
+// GetMTGCatalogChanges returns the catalog changes recorded by imports after since
+// (an RFC 3339 timestamp), newest import first and by card name within an import.
+func GetMTGCatalogChanges(ctx context.Context, since string) ([]*model.MtgCatalogChange, error) {
+	log.Info().Str("since", since).Msg("GetMTGCatalogChanges: Started")
+
+	sinceTime, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return nil, fmt.Errorf("invalid since timestamp %q: %w", since, err)
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR change IN mtg_catalog_changes
+            FILTER change.importedAt > @since
+            SORT change.importedAt DESC, change.name ASC
+            RETURN change
+    `)
+
+	aq.AddBindVar("since", sinceTime.UTC().Format(time.RFC3339))
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGCatalogChanges: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	changes := []*model.MtgCatalogChange{}
+	for cursor.HasMore() {
+		var change model.MtgCatalogChange
+		if _, err := cursor.ReadDocument(ctx, &change); err != nil {
+			log.Error().Err(err).Msg("GetMTGCatalogChanges: Error reading document")
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+
+	log.Info().Int("changes", len(changes)).Msg("GetMTGCatalogChanges: Finished")
+	return changes, nil
+}
+
+// GetMTGLegalityChanges returns the legality changes recorded by imports after
+// since (an RFC 3339 timestamp), newest first, limited to format when given.
+func GetMTGLegalityChanges(ctx context.Context, since string, format *string) ([]*model.MtgLegalityChange, error) {
+	log.Info().Str("since", since).Msg("GetMTGLegalityChanges: Started")
+
+	sinceTime, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return nil, fmt.Errorf("invalid since timestamp %q: %w", since, err)
+	}
+
+	formatKey := ""
+	if format != nil {
+		formatKey = strings.ToLower(strings.TrimSpace(*format))
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR change IN mtg_legality_changes
+            FILTER change.changedAt > @since
+            FILTER @format == "" OR change.format == @format
+            SORT change.changedAt DESC, change.name ASC, change.format ASC
+            RETURN change
+    `)
+
+	aq.AddBindVar("since", sinceTime.UTC().Format(time.RFC3339))
+	aq.AddBindVar("format", formatKey)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGLegalityChanges: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	changes := []*model.MtgLegalityChange{}
+	for cursor.HasMore() {
+		var change model.MtgLegalityChange
+		if _, err := cursor.ReadDocument(ctx, &change); err != nil {
+			log.Error().Err(err).Msg("GetMTGLegalityChanges: Error reading document")
+			return nil, err
+		}
+		changes = append(changes, &change)
+	}
+
+	log.Info().Int("changes", len(changes)).Msg("GetMTGLegalityChanges: Finished")
+	return changes, nil
+}
+
+// GetMTGQuarantinedRecords returns the records the import run runID quarantined,
+// or those of the latest finished run when runID is empty, by kind and record ID.
+func GetMTGQuarantinedRecords(ctx context.Context, runID *string) ([]*model.MtgQuarantinedRecord, error) {
+	log.Info().Msg("GetMTGQuarantinedRecords: Started")
+
+	runKey := ""
+	if runID != nil {
+		runKey = strings.TrimSpace(*runID)
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+        LET runID = @runID != "" ? @runID : FIRST(
+            FOR run IN mtg_import_runs
+                SORT run.startedAt DESC
+                LIMIT 1
+                RETURN run._key
+        )
+        FOR record IN mtg_quarantine
+            FILTER record.runID == runID
+            SORT record.kind ASC, record.recordID ASC
+            RETURN record
+    `)
+
+	aq.AddBindVar("runID", runKey)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGQuarantinedRecords: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	records := []*model.MtgQuarantinedRecord{}
+	for cursor.HasMore() {
+		var record model.MtgQuarantinedRecord
+		if _, err := cursor.ReadDocument(ctx, &record); err != nil {
+			log.Error().Err(err).Msg("GetMTGQuarantinedRecords: Error reading document")
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+
+	log.Info().Int("records", len(records)).Msg("GetMTGQuarantinedRecords: Finished")
+	return records, nil
+}
@@ -0,0 +1,87 @@
+package mtg
+
+import (
+	"context"
+	"time"
+
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultPriceHistoryDays is the number of days returned when no length is given.
+const defaultPriceHistoryDays = 30
+
+// GetMTGCardVersionPrices returns the latest recorded prices of a card version,
+// or nil when none were recorded.
+func GetMTGCardVersionPrices(ctx context.Context, versionID string) (*model.MtgPrices, error) {
+	aq := arango.NewQuery( /* aql */ `
+        FOR price IN mtg_card_prices
+            FILTER price.versionID == @versionID
+            SORT price.date DESC
+            LIMIT 1
+            RETURN price
+    `)
+
+	aq.AddBindVar("versionID", versionID)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGCardVersionPrices: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	if !cursor.HasMore() {
+		return nil, nil
+	}
+	var price model.MTGCardPriceDB
+	if _, err := cursor.ReadDocument(ctx, &price); err != nil {
+		log.Error().Err(err).Msg("GetMTGCardVersionPrices: Error reading document")
+		return nil, err
+	}
+	return &price.Prices, nil
+}
+
+// GetMTGCardVersionPriceHistory returns the daily price snapshots of a card
+// version over the last days, oldest first.
+func GetMTGCardVersionPriceHistory(ctx context.Context, versionID string, days *int) ([]*model.MtgPricePoint, error) {
+	log.Info().Str("versionID", versionID).Msg("GetMTGCardVersionPriceHistory: Started")
+
+	count := defaultPriceHistoryDays
+	if days != nil && *days > 0 {
+		count = *days
+	}
+	since := time.Now().UTC().AddDate(0, 0, -count).Format("2006-01-02")
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR price IN mtg_card_prices
+            FILTER price.versionID == @versionID AND price.date > @since
+            SORT price.date ASC
+            RETURN price
+    `)
+
+	aq.AddBindVar("versionID", versionID)
+	aq.AddBindVar("since", since)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGCardVersionPriceHistory: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	history := []*model.MtgPricePoint{}
+	for cursor.HasMore() {
+		var price model.MTGCardPriceDB
+		if _, err := cursor.ReadDocument(ctx, &price); err != nil {
+			log.Error().Err(err).Msg("GetMTGCardVersionPriceHistory: Error reading document")
+			return nil, err
+		}
+		history = append(history, price.ToModel())
+	}
+
+	log.Info().Int("points", len(history)).Msg("GetMTGCardVersionPriceHistory: Finished")
+	return history, nil
+}
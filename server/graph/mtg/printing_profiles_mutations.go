@@ -0,0 +1,336 @@
+package mtg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+var errPrintingProfileNotFound = errors.New("printing profile not found")
+
+// CreateMTGPrintingProfile stores a new printing profile without pins.
+func CreateMTGPrintingProfile(ctx context.Context, input model.MtgCreatePrintingProfileInput) (*model.MtgPrintingProfile, error) {
+	log.Info().Str("name", input.Name).Msg("CreateMTGPrintingProfile: Started")
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	rules, err := printingRulesFromInput(input.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+        INSERT {
+            name: @name,
+            rules: @rules,
+            pins: [],
+            isGlobal: false
+        } INTO mtg_printing_profiles
+        RETURN NEW
+    `)
+	aq.AddBindVar("name", name)
+	aq.AddBindVar("rules", rules)
+
+	profile, err := readPrintingProfile(ctx, aq)
+	if err != nil {
+		log.Error().Err(err).Msg("CreateMTGPrintingProfile: Error inserting profile")
+		return nil, err
+	}
+
+	log.Info().Str("profileID", profile.ID).Msg("CreateMTGPrintingProfile: Finished")
+	return profile, nil
+}
+
+// UpdateMTGPrintingProfile renames a printing profile and/or replaces its rules.
+func UpdateMTGPrintingProfile(ctx context.Context, input model.MtgUpdatePrintingProfileInput) (*model.MtgPrintingProfile, error) {
+	log.Info().Str("profileID", input.ProfileID).Msg("UpdateMTGPrintingProfile: Started")
+
+	updateFields := map[string]any{}
+	if input.Name != nil {
+		trimmed := strings.TrimSpace(*input.Name)
+		if trimmed == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+		updateFields["name"] = trimmed
+	}
+	if input.Rules != nil {
+		rules, err := printingRulesFromInput(input.Rules)
+		if err != nil {
+			return nil, err
+		}
+		updateFields["rules"] = rules
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR profile IN mtg_printing_profiles
+            FILTER profile._key == @profileID
+            UPDATE profile WITH @updates IN mtg_printing_profiles
+            RETURN NEW
+    `)
+	aq.AddBindVar("profileID", input.ProfileID)
+	aq.AddBindVar("updates", updateFields)
+
+	profile, err := readPrintingProfile(ctx, aq)
+	if err != nil {
+		log.Error().Err(err).Msg("UpdateMTGPrintingProfile: Error updating profile")
+		return nil, err
+	}
+
+	log.Info().Str("profileID", profile.ID).Msg("UpdateMTGPrintingProfile: Finished")
+	return profile, nil
+}
+
+// DeleteMTGPrintingProfile removes a printing profile and clears it from the decks
+// using it.
+func DeleteMTGPrintingProfile(ctx context.Context, input model.MtgDeletePrintingProfileInput) (*model.Response, error) {
+	log.Info().Str("profileID", input.ProfileID).Msg("DeleteMTGPrintingProfile: Started")
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR profile IN mtg_printing_profiles
+            FILTER profile._key == @profileID
+            REMOVE profile IN mtg_printing_profiles
+            RETURN OLD._key
+    `)
+	aq.AddBindVar("profileID", input.ProfileID)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("DeleteMTGPrintingProfile: Error deleting profile")
+		return nil, err
+	}
+	removed := cursor.HasMore()
+	cursor.Close()
+	if !removed {
+		return nil, errPrintingProfileNotFound
+	}
+
+	clearDecks := arango.NewQuery( /* aql */ `
+        FOR deck IN mtg_decks
+            FILTER deck.printingProfileID == @profileID
+            UPDATE deck WITH { printingProfileID: null } IN mtg_decks OPTIONS { keepNull: false }
+    `)
+	clearDecks.AddBindVar("profileID", input.ProfileID)
+
+	if _, err := arango.DB.Query(ctx, clearDecks.Query, clearDecks.BindVars); err != nil {
+		log.Error().Err(err).Msg("DeleteMTGPrintingProfile: Error clearing deck profiles")
+		return nil, err
+	}
+
+	log.Info().Str("profileID", input.ProfileID).Msg("DeleteMTGPrintingProfile: Finished")
+	return &model.Response{Status: true}, nil
+}
+
+// PinMTGCardVersion fixes the default version of a card within a printing profile.
+// The version must belong to the card.
+func PinMTGCardVersion(ctx context.Context, input model.MtgPinCardVersionInput) (*model.MtgPrintingProfile, error) {
+	log.Info().Str("profileID", input.ProfileID).Str("cardID", input.CardID).Str("versionID", input.VersionID).Msg("PinMTGCardVersion: Started")
+
+	check := arango.NewQuery( /* aql */ `
+        LET card = DOCUMENT("mtg_cards", @cardID)
+        RETURN card != null AND @versionID IN card.versions[*].ID
+    `)
+	check.AddBindVar("cardID", input.CardID)
+	check.AddBindVar("versionID", input.VersionID)
+
+	cursor, err := arango.DB.Query(ctx, check.Query, check.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("PinMTGCardVersion: Error checking card version")
+		return nil, err
+	}
+	var found bool
+	if cursor.HasMore() {
+		if _, err := cursor.ReadDocument(ctx, &found); err != nil {
+			cursor.Close()
+			log.Error().Err(err).Msg("PinMTGCardVersion: Error reading card version check")
+			return nil, err
+		}
+	}
+	cursor.Close()
+	if !found {
+		return nil, fmt.Errorf("card %s has no version %s", input.CardID, input.VersionID)
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR profile IN mtg_printing_profiles
+            FILTER profile._key == @profileID
+            LET pins = APPEND(
+                (FOR pin IN profile.pins || [] FILTER pin.cardID != @cardID RETURN pin),
+                [{ cardID: @cardID, versionID: @versionID }]
+            )
+            UPDATE profile WITH { pins: pins } IN mtg_printing_profiles
+            RETURN NEW
+    `)
+	aq.AddBindVar("profileID", input.ProfileID)
+	aq.AddBindVar("cardID", input.CardID)
+	aq.AddBindVar("versionID", input.VersionID)
+
+	profile, err := readPrintingProfile(ctx, aq)
+	if err != nil {
+		log.Error().Err(err).Msg("PinMTGCardVersion: Error updating profile")
+		return nil, err
+	}
+
+	log.Info().Str("profileID", profile.ID).Msg("PinMTGCardVersion: Finished")
+	return profile, nil
+}
+
+// UnpinMTGCardVersion removes a card's pin from a printing profile.
+func UnpinMTGCardVersion(ctx context.Context, input model.MtgUnpinCardVersionInput) (*model.MtgPrintingProfile, error) {
+	log.Info().Str("profileID", input.ProfileID).Str("cardID", input.CardID).Msg("UnpinMTGCardVersion: Started")
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR profile IN mtg_printing_profiles
+            FILTER profile._key == @profileID
+            LET pins = (FOR pin IN profile.pins || [] FILTER pin.cardID != @cardID RETURN pin)
+            UPDATE profile WITH { pins: pins } IN mtg_printing_profiles
+            RETURN NEW
+    `)
+	aq.AddBindVar("profileID", input.ProfileID)
+	aq.AddBindVar("cardID", input.CardID)
+
+	profile, err := readPrintingProfile(ctx, aq)
+	if err != nil {
+		log.Error().Err(err).Msg("UnpinMTGCardVersion: Error updating profile")
+		return nil, err
+	}
+
+	log.Info().Str("profileID", profile.ID).Msg("UnpinMTGCardVersion: Finished")
+	return profile, nil
+}
+
+// SetMTGGlobalPrintingProfile makes profileID the only global printing profile, or
+// leaves no global profile when profileID is nil.
+func SetMTGGlobalPrintingProfile(ctx context.Context, profileID *string) (*model.Response, error) {
+	key := ""
+	if profileID != nil {
+		key = strings.TrimSpace(*profileID)
+	}
+	log.Info().Str("profileID", key).Msg("SetMTGGlobalPrintingProfile: Started")
+
+	if key != "" {
+		if _, err := getPrintingProfileByID(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR profile IN mtg_printing_profiles
+            FILTER profile.isGlobal == true OR profile._key == @profileID
+            UPDATE profile WITH { isGlobal: profile._key == @profileID } IN mtg_printing_profiles
+    `)
+	aq.AddBindVar("profileID", key)
+
+	if _, err := arango.DB.Query(ctx, aq.Query, aq.BindVars); err != nil {
+		log.Error().Err(err).Msg("SetMTGGlobalPrintingProfile: Error updating profiles")
+		return nil, err
+	}
+
+	log.Info().Str("profileID", key).Msg("SetMTGGlobalPrintingProfile: Finished")
+	return &model.Response{Status: true}, nil
+}
+
+// SetMTGDeckPrintingProfile stores the printing profile of a deck, or removes it
+// when ProfileID is nil so the deck follows the global profile.
+func SetMTGDeckPrintingProfile(ctx context.Context, input model.MtgSetDeckPrintingProfileInput) (*model.Response, error) {
+	log.Info().Str("deckID", input.DeckID).Msg("SetMTGDeckPrintingProfile: Started")
+
+	var profileID any
+	if input.ProfileID != nil && strings.TrimSpace(*input.ProfileID) != "" {
+		key := strings.TrimSpace(*input.ProfileID)
+		if _, err := getPrintingProfileByID(ctx, key); err != nil {
+			return nil, err
+		}
+		profileID = key
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR deck IN mtg_decks
+            FILTER deck._key == @deckID
+            UPDATE deck WITH { printingProfileID: @profileID } IN mtg_decks OPTIONS { keepNull: false }
+            RETURN NEW._key
+    `)
+	aq.AddBindVar("deckID", input.DeckID)
+	aq.AddBindVar("profileID", profileID)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("SetMTGDeckPrintingProfile: Error updating deck")
+		return nil, err
+	}
+	defer cursor.Close()
+	if !cursor.HasMore() {
+		return nil, errors.New("deck not found")
+	}
+
+	log.Info().Str("deckID", input.DeckID).Msg("SetMTGDeckPrintingProfile: Finished")
+	return &model.Response{Status: true}, nil
+}
+
+// printingRulesFromInput validates rule inputs: every kind but OLDEST and NEWEST
+// needs a value.
+func printingRulesFromInput(input []*model.MtgPrintingRuleInput) ([]*model.MtgPrintingRule, error) {
+	rules := make([]*model.MtgPrintingRule, 0, len(input))
+	for i, ruleInput := range input {
+		if ruleInput == nil {
+			continue
+		}
+		if !ruleInput.Kind.IsValid() {
+			return nil, fmt.Errorf("rule %d: invalid kind %q", i+1, ruleInput.Kind)
+		}
+		rule := &model.MtgPrintingRule{Kind: ruleInput.Kind}
+		if ruleInput.Avoid != nil {
+			rule.Avoid = *ruleInput.Avoid
+		}
+		switch ruleInput.Kind {
+		case model.MtgPrintingRuleKindOldest, model.MtgPrintingRuleKindNewest:
+		default:
+			if ruleInput.Value == nil || strings.TrimSpace(*ruleInput.Value) == "" {
+				return nil, fmt.Errorf("rule %d: %s needs a value", i+1, ruleInput.Kind)
+			}
+			value := strings.TrimSpace(*ruleInput.Value)
+			rule.Value = &value
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// getPrintingProfileByID loads a printing profile, failing when it does not exist.
+func getPrintingProfileByID(ctx context.Context, profileID string) (*model.MtgPrintingProfile, error) {
+	aq := arango.NewQuery( /* aql */ `
+        FOR profile IN mtg_printing_profiles
+            FILTER profile._key == @profileID
+            LIMIT 1
+            RETURN profile
+    `)
+	aq.AddBindVar("profileID", profileID)
+
+	return readPrintingProfile(ctx, aq)
+}
+
+// readPrintingProfile runs a query returning one profile document; no result means
+// the profile does not exist.
+func readPrintingProfile(ctx context.Context, aq arango.Query) (*model.MtgPrintingProfile, error) {
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	if !cursor.HasMore() {
+		return nil, errPrintingProfileNotFound
+	}
+	var profile model.MtgPrintingProfile
+	if _, err := cursor.ReadDocument(ctx, &profile); err != nil {
+		return nil, err
+	}
+	return normalizePrintingProfile(&profile), nil
+}
@@ -0,0 +1,262 @@
+package mtg
+
+import (
+	"context"
+	"strings"
+
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+// GetMTGPrintingProfiles returns all printing profiles sorted by name.
+func GetMTGPrintingProfiles(ctx context.Context) ([]*model.MtgPrintingProfile, error) {
+	log.Info().Msg("GetMTGPrintingProfiles: Started")
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR profile IN mtg_printing_profiles
+            SORT profile.name ASC, profile._key ASC
+            RETURN profile
+    `)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGPrintingProfiles: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	profiles := []*model.MtgPrintingProfile{}
+	for cursor.HasMore() {
+		var profile model.MtgPrintingProfile
+		if _, err := cursor.ReadDocument(ctx, &profile); err != nil {
+			log.Error().Err(err).Msg("GetMTGPrintingProfiles: Error reading document")
+			return nil, err
+		}
+		profiles = append(profiles, normalizePrintingProfile(&profile))
+	}
+
+	log.Info().Int("profiles", len(profiles)).Msg("GetMTGPrintingProfiles: Finished")
+	return profiles, nil
+}
+
+// ApplyPrintingProfileForDeck marks the default version of each card according to
+// the printing profile of the deck, or the global profile when deckID is empty or
+// the deck has none. Cards are returned unchanged when no profile applies; changed
+// cards are copies, so cached cards are never mutated.
+func ApplyPrintingProfileForDeck(ctx context.Context, deckID *string, cards []*model.MtgCard) ([]*model.MtgCard, error) {
+	profile, err := resolvePrintingProfile(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return cards, nil
+	}
+	return applyPrintingProfile(profile, cards), nil
+}
+
+// resolvePrintingProfile returns the deck's printing profile, falling back to the
+// global one, or nil when neither exists. Pins still stored under a retired card
+// key are resolved through mtg_card_key_aliases, so they keep applying until
+// the import migrates them; they are listed after the pins made under current
+// keys.
+func resolvePrintingProfile(ctx context.Context, deckID *string) (*model.MtgPrintingProfile, error) {
+	deckKey := ""
+	if deckID != nil {
+		deckKey = strings.TrimSpace(*deckID)
+	}
+
+	aq := arango.NewQuery( /* aql */ `
+        LET deckProfileID = @deckID == "" ? null : DOCUMENT("mtg_decks", @deckID).printingProfileID
+        LET deckProfile = deckProfileID == null ? null : DOCUMENT("mtg_printing_profiles", deckProfileID)
+        LET globalProfile = FIRST(
+            FOR profile IN mtg_printing_profiles
+                FILTER profile.isGlobal == true
+                LIMIT 1
+                RETURN profile
+        )
+        LET profile = deckProfile != null ? deckProfile : globalProfile
+        RETURN profile == null ? null : MERGE(profile, {
+            pins: (
+                FOR pin IN profile.pins || []
+                    LET alias = DOCUMENT("mtg_card_key_aliases", pin.cardID)
+                    LET moved = alias != null AND DOCUMENT("mtg_cards", pin.cardID) == null
+                    SORT moved ASC
+                    RETURN moved ? MERGE(pin, { cardID: alias.cardKey }) : pin
+            )
+        })
+    `)
+	aq.AddBindVar("deckID", deckKey)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("resolvePrintingProfile: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	var profile *model.MtgPrintingProfile
+	if cursor.HasMore() {
+		if _, err := cursor.ReadDocument(ctx, &profile); err != nil {
+			log.Error().Err(err).Msg("resolvePrintingProfile: Error reading document")
+			return nil, err
+		}
+	}
+	if profile == nil || (len(profile.Rules) == 0 && len(profile.Pins) == 0) {
+		return nil, nil
+	}
+	return profile, nil
+}
+
+// applyPrintingProfile moves isDefault to the version the profile prefers. A pinned
+// version wins when the card still has it; otherwise the rules decide and the
+// built-in defaultRank breaks ties.
+func applyPrintingProfile(profile *model.MtgPrintingProfile, cards []*model.MtgCard) []*model.MtgCard {
+	pins := make(map[string]string, len(profile.Pins))
+	for _, pin := range profile.Pins {
+		if pin == nil {
+			continue
+		}
+		// A pin resolved from a retired key never overrides one made under the
+		// current key.
+		if _, ok := pins[pin.CardID]; !ok {
+			pins[pin.CardID] = pin.VersionID
+		}
+	}
+
+	result := make([]*model.MtgCard, len(cards))
+	for i, card := range cards {
+		result[i] = card
+		if card == nil || len(card.Versions) == 0 {
+			continue
+		}
+
+		best := -1
+		if versionID, ok := pins[card.ID]; ok {
+			for j, v := range card.Versions {
+				if v != nil && v.ID == versionID {
+					best = j
+					break
+				}
+			}
+		}
+		if best < 0 {
+			for j, v := range card.Versions {
+				if v == nil {
+					continue
+				}
+				if best < 0 || preferVersion(profile.Rules, v, card.Versions[best]) {
+					best = j
+				}
+			}
+		}
+
+		unchanged := true
+		for j, v := range card.Versions {
+			if v != nil && v.IsDefault != (j == best) {
+				unchanged = false
+				break
+			}
+		}
+		if unchanged {
+			continue
+		}
+
+		cardCopy := *card
+		cardCopy.Versions = make([]*model.MtgCardVersion, len(card.Versions))
+		for j, v := range card.Versions {
+			if v == nil {
+				continue
+			}
+			versionCopy := *v
+			versionCopy.IsDefault = j == best
+			cardCopy.Versions[j] = &versionCopy
+		}
+		result[i] = &cardCopy
+	}
+	return result
+}
+
+// preferVersion reports whether a should be the default over b under rules.
+func preferVersion(rules []*model.MtgPrintingRule, a, b *model.MtgCardVersion) bool {
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		switch rule.Kind {
+		case model.MtgPrintingRuleKindOldest:
+			if a.ReleasedAt != b.ReleasedAt && a.ReleasedAt != "" && b.ReleasedAt != "" {
+				return a.ReleasedAt < b.ReleasedAt
+			}
+		case model.MtgPrintingRuleKindNewest:
+			if a.ReleasedAt != b.ReleasedAt && a.ReleasedAt != "" && b.ReleasedAt != "" {
+				return a.ReleasedAt > b.ReleasedAt
+			}
+		default:
+			am, bm := matchesPrintingRule(rule, a), matchesPrintingRule(rule, b)
+			if am != bm {
+				return am != rule.Avoid
+			}
+		}
+	}
+	if a.DefaultRank != b.DefaultRank {
+		return a.DefaultRank < b.DefaultRank
+	}
+	return a.ID < b.ID
+}
+
+// matchesPrintingRule reports whether a version has the attribute a rule looks for.
+func matchesPrintingRule(rule *model.MtgPrintingRule, v *model.MtgCardVersion) bool {
+	if rule.Value == nil {
+		return false
+	}
+	value := *rule.Value
+	switch rule.Kind {
+	case model.MtgPrintingRuleKindSet:
+		return strings.EqualFold(v.Set, value)
+	case model.MtgPrintingRuleKindBorderColor:
+		return strings.EqualFold(v.BorderColor, value)
+	case model.MtgPrintingRuleKindFrame:
+		return strings.EqualFold(v.Frame, value)
+	case model.MtgPrintingRuleKindFrameEffect:
+		return containsFold(v.FrameEffects, value)
+	case model.MtgPrintingRuleKindFinish:
+		return containsFold(v.Finishes, value)
+	case model.MtgPrintingRuleKindPromoType:
+		return containsFold(v.PromoTypes, value)
+	case model.MtgPrintingRuleKindArtist:
+		return v.Artist != nil && strings.EqualFold(*v.Artist, value)
+	case model.MtgPrintingRuleKindLanguage:
+		return strings.EqualFold(v.Lang, value)
+	case model.MtgPrintingRuleKindGame:
+		for _, game := range v.Games {
+			if strings.EqualFold(string(game), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsFold reports whether items holds target, ignoring case.
+func containsFold(items []string, target string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePrintingProfile replaces missing lists so the non-null GraphQL fields
+// always resolve.
+func normalizePrintingProfile(profile *model.MtgPrintingProfile) *model.MtgPrintingProfile {
+	if profile.Rules == nil {
+		profile.Rules = []*model.MtgPrintingRule{}
+	}
+	if profile.Pins == nil {
+		profile.Pins = []*model.MtgPrintingPin{}
+	}
+	return profile
+}
@@ -0,0 +1,65 @@
+package mtg
+
+import (
+	"context"
+
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+// GetMTGRelatedCards returns the cards linked to a card through all_parts,
+// grouped by component and sorted by name.
+func GetMTGRelatedCards(ctx context.Context, cardID string) ([]*model.MtgRelatedCard, error) {
+	log.Info().Str("cardID", cardID).Msg("GetMTGRelatedCards: Started")
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR card, edge IN 1..1 OUTBOUND CONCAT("mtg_cards/", @cardID) mtg_card_related_card
+            LET tagAssignments = (
+                FOR tag, tagEdge IN 1..1 INBOUND card mtg_tag_to_card
+                LET chainTags = (
+                    FOR chainTagID IN (tagEdge.chain || [])
+                        LET chainTag = DOCUMENT("mtg_tags", chainTagID)
+                        RETURN { _key: chainTag._key, name: chainTag.name, meta: chainTag.meta || false }
+                )
+                LET allTagNames = APPEND(
+                    (FOR ct IN chainTags RETURN ct.name),
+                    [tag.name]
+                )
+                SORT tag.name ASC
+                RETURN {
+                    tag: { _key: tag._key, name: tag.name, meta: tag.meta || false },
+                    chain: chainTags,
+                    chainDisplay: CONCAT_SEPARATOR(" → ", allTagNames)
+                }
+            )
+            SORT edge.component ASC, card.name ASC
+            RETURN {
+                component: edge.component,
+                card: MERGE(card, { tagAssignments: tagAssignments })
+            }
+    `)
+
+	aq.AddBindVar("cardID", cardID)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGRelatedCards: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	related := []*model.MtgRelatedCard{}
+	for cursor.HasMore() {
+		var relatedCard model.MtgRelatedCard
+		if _, err := cursor.ReadDocument(ctx, &relatedCard); err != nil {
+			log.Error().Err(err).Msg("GetMTGRelatedCards: Error reading document")
+			return nil, err
+		}
+		related = append(related, &relatedCard)
+	}
+
+	log.Info().Int("relatedCards", len(related)).Msg("GetMTGRelatedCards: Finished")
+	return related, nil
+}
@@ -0,0 +1,40 @@
+package mtg
+
+import (
+	"context"
+
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+// GetMTGCardRulings returns the rulings of a card, oldest first. Cards without
+// rulings get an empty list.
+func GetMTGCardRulings(ctx context.Context, cardID string) ([]*model.MtgRuling, error) {
+	log.Info().Str("cardID", cardID).Msg("GetMTGCardRulings: Started")
+
+	aq := arango.NewQuery( /* aql */ `
+        RETURN DOCUMENT("mtg_card_rulings", @cardID).rulings || []
+    `)
+
+	aq.AddBindVar("cardID", cardID)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGCardRulings: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	rulings := []*model.MtgRuling{}
+	if cursor.HasMore() {
+		if _, err := cursor.ReadDocument(ctx, &rulings); err != nil {
+			log.Error().Err(err).Msg("GetMTGCardRulings: Error reading document")
+			return nil, err
+		}
+	}
+
+	log.Info().Int("rulings", len(rulings)).Msg("GetMTGCardRulings: Finished")
+	return rulings, nil
+}
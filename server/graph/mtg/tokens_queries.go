@@ -0,0 +1,106 @@
+package mtg
+
+import (
+	"context"
+	"strings"
+
+	"magic-helper/arango"
+	"magic-helper/graph/model"
+
+	"github.com/rs/zerolog/log"
+)
+
+// GetMTGTokens returns one page of the token catalog, sorted by name. A search
+// matches the name, type line or rules text, case-insensitively.
+func GetMTGTokens(ctx context.Context, search *string, pagination model.MtgFilterPaginationInput) (*model.MtgTokenSearch, error) {
+	log.Info().Int("page", pagination.Page).Int("pageSize", pagination.PageSize).Msg("GetMTGTokens: Started")
+
+	searchLower := ""
+	if search != nil {
+		searchLower = strings.ToLower(strings.TrimSpace(*search))
+	}
+	offset := max(pagination.Page, 0) * max(pagination.PageSize, 0)
+
+	aq := arango.NewQuery( /* aql */ `
+        LET matches = (
+            FOR token IN mtg_tokens
+                FILTER @search == ""
+                    OR CONTAINS(LOWER(token.name), @search)
+                    OR CONTAINS(LOWER(token.typeLine), @search)
+                    OR CONTAINS(LOWER(token.oracleText || ""), @search)
+                SORT token.name ASC, token._key ASC
+                RETURN token
+        )
+        RETURN {
+            totalCount: LENGTH(matches),
+            pagedTokens: (
+                FOR token IN SLICE(matches, @offset, @count)
+                    RETURN MERGE(token, { tagAssignments: [] })
+            )
+        }
+    `)
+
+	aq.AddBindVar("search", searchLower)
+	aq.AddBindVar("offset", offset)
+	aq.AddBindVar("count", max(pagination.PageSize, 0))
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGTokens: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	result := &model.MtgTokenSearch{PagedTokens: []*model.MtgCard{}}
+	if cursor.HasMore() {
+		if _, err := cursor.ReadDocument(ctx, result); err != nil {
+			log.Error().Err(err).Msg("GetMTGTokens: Error reading document")
+			return nil, err
+		}
+	}
+
+	log.Info().Int("tokens", len(result.PagedTokens)).Int("totalCount", result.TotalCount).Msg("GetMTGTokens: Finished")
+	return result, nil
+}
+
+// GetMTGDeckTokens lists the tokens and emblems the cards of a deck can create,
+// following the all_parts relations of each card, with the names of the cards
+// creating them.
+func GetMTGDeckTokens(ctx context.Context, deckID string) ([]*model.MtgDeckToken, error) {
+	log.Info().Str("deckID", deckID).Msg("GetMTGDeckTokens: Started")
+
+	aq := arango.NewQuery( /* aql */ `
+        FOR card IN 1..1 INBOUND CONCAT("mtg_decks/", @deckID) mtg_card_deck
+            FOR token IN 1..1 OUTBOUND card mtg_card_related_card
+                FILTER IS_SAME_COLLECTION("mtg_tokens", token)
+                COLLECT tokenID = token._id INTO creators = card.name
+                LET doc = DOCUMENT(tokenID)
+                SORT doc.name ASC, doc._key ASC
+                RETURN {
+                    token: MERGE(doc, { tagAssignments: [] }),
+                    createdBy: SORTED_UNIQUE(creators)
+                }
+    `)
+
+	aq.AddBindVar("deckID", deckID)
+
+	cursor, err := arango.DB.Query(ctx, aq.Query, aq.BindVars)
+	if err != nil {
+		log.Error().Err(err).Msg("GetMTGDeckTokens: Error querying database")
+		return nil, err
+	}
+	defer cursor.Close()
+
+	tokens := []*model.MtgDeckToken{}
+	for cursor.HasMore() {
+		var token model.MtgDeckToken
+		if _, err := cursor.ReadDocument(ctx, &token); err != nil {
+			log.Error().Err(err).Msg("GetMTGDeckTokens: Error reading document")
+			return nil, err
+		}
+		tokens = append(tokens, &token)
+	}
+
+	log.Info().Int("tokens", len(tokens)).Msg("GetMTGDeckTokens: Finished")
+	return tokens, nil
+}
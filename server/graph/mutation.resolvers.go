@@ -49,6 +49,41 @@ func (r *mutationResolver) DeleteMTGFilterPreset(ctx context.Context, input mode
 	return mtg.DeleteMTGFilterPreset(ctx, input)
 }
 
+// CreateMTGPrintingProfile is the resolver for the createMTGPrintingProfile field.
+func (r *mutationResolver) CreateMTGPrintingProfile(ctx context.Context, input model.MtgCreatePrintingProfileInput) (*model.MtgPrintingProfile, error) {
+	return mtg.CreateMTGPrintingProfile(ctx, input)
+}
+
+// UpdateMTGPrintingProfile is the resolver for the updateMTGPrintingProfile field.
+func (r *mutationResolver) UpdateMTGPrintingProfile(ctx context.Context, input model.MtgUpdatePrintingProfileInput) (*model.MtgPrintingProfile, error) {
+	return mtg.UpdateMTGPrintingProfile(ctx, input)
+}
+
+// DeleteMTGPrintingProfile is the resolver for the deleteMTGPrintingProfile field.
+func (r *mutationResolver) DeleteMTGPrintingProfile(ctx context.Context, input model.MtgDeletePrintingProfileInput) (*model.Response, error) {
+	return mtg.DeleteMTGPrintingProfile(ctx, input)
+}
+
+// PinMTGCardVersion is the resolver for the pinMTGCardVersion field.
+func (r *mutationResolver) PinMTGCardVersion(ctx context.Context, input model.MtgPinCardVersionInput) (*model.MtgPrintingProfile, error) {
+	return mtg.PinMTGCardVersion(ctx, input)
+}
+
+// UnpinMTGCardVersion is the resolver for the unpinMTGCardVersion field.
+func (r *mutationResolver) UnpinMTGCardVersion(ctx context.Context, input model.MtgUnpinCardVersionInput) (*model.MtgPrintingProfile, error) {
+	return mtg.UnpinMTGCardVersion(ctx, input)
+}
+
+// SetMTGGlobalPrintingProfile is the resolver for the setMTGGlobalPrintingProfile field.
+func (r *mutationResolver) SetMTGGlobalPrintingProfile(ctx context.Context, profileID *string) (*model.Response, error) {
+	return mtg.SetMTGGlobalPrintingProfile(ctx, profileID)
+}
+
+// SetMTGDeckPrintingProfile is the resolver for the setMTGDeckPrintingProfile field.
+func (r *mutationResolver) SetMTGDeckPrintingProfile(ctx context.Context, input model.MtgSetDeckPrintingProfileInput) (*model.Response, error) {
+	return mtg.SetMTGDeckPrintingProfile(ctx, input)
+}
+
 // AddIgnoredCard is the resolver for the addIgnoredCard field.
 func (r *mutationResolver) AddIgnoredCard(ctx context.Context, input model.AddIgnoredCardInput) (*model.Response, error) {
 	return mtg.AddIgnoredCard(ctx, input)
@@ -95,9 +130,35 @@ func (r *mutationResolver) UnassignTagFromDeck(ctx context.Context, input model.
 }
 
 // ReimportMTGData is the resolver for the reimportMTGData field.
-func (r *mutationResolver) ReimportMTGData(ctx context.Context) (*model.MtgImportStatus, error) {
+func (r *mutationResolver) ReimportMTGData(ctx context.Context, source *model.MtgImportSourceInput) (*model.MtgImportStatus, error) {
+	importSource := daemons.DefaultImportSource()
+	if source != nil {
+		// Files passed by clients are confined to import.importDir.
+		for _, file := range []struct {
+			name   *string
+			target *string
+		}{
+			{source.SetsFile, &importSource.SetsFile},
+			{source.CardsFile, &importSource.CardsFile},
+			{source.RulingsFile, &importSource.RulingsFile},
+		} {
+			if file.name == nil {
+				continue
+			}
+			if *file.name == "" {
+				*file.target = ""
+				continue
+			}
+			path, err := daemons.ResolveImportFile(*file.name)
+			if err != nil {
+				return nil, err
+			}
+			*file.target = path
+		}
+	}
+
 	manager := daemons.GetImportManager()
-	started, message, inProgress := manager.TriggerImport()
+	started, message, inProgress := manager.TriggerImport(importSource)
 
 	// Determine the initial phase
 	phase := model.MtgImportPhaseIdle
@@ -113,9 +174,16 @@ func (r *mutationResolver) ReimportMTGData(ctx context.Context) (*model.MtgImpor
 		InProgress: inProgress,
 		Phase:      phase,
 		Progress:   progress,
+		Counters:   &model.MtgImportCounters{},
 	}, nil
 }
 
+// CancelMTGImport is the resolver for the cancelMTGImport field.
+func (r *mutationResolver) CancelMTGImport(ctx context.Context) (*model.Response, error) {
+	cancelled, message := daemons.GetImportManager().CancelImport()
+	return &model.Response{Status: cancelled, Message: &message}, nil
+}
+
 // Mutation returns gentypes.MutationResolver implementation.
 func (r *Resolver) Mutation() gentypes.MutationResolver { return &mutationResolver{r} }
 
@@ -16,7 +16,11 @@ import (
 // This layer remains thin, mapping GraphQL to service functions.
 // GetMTGCards is the resolver for the getMTGCards field.
 func (r *queryResolver) GetMTGCards(ctx context.Context) ([]*model.MtgCard, error) {
-	return mtg.GetMTGCards(ctx)
+	cards, err := mtg.GetMTGCards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mtg.ApplyPrintingProfileForDeck(ctx, nil, cards)
 }
 
 // GetMTGCardsFiltered is the resolver for the getMTGCardsFiltered field.
@@ -29,6 +33,16 @@ func (r *queryResolver) GetMTGFilters(ctx context.Context) (*model.MtgFilterEntr
 	return mtg.GetMTGFilters(ctx)
 }
 
+// GetMTGTokens is the resolver for the getMTGTokens field.
+func (r *queryResolver) GetMTGTokens(ctx context.Context, search *string, pagination model.MtgFilterPaginationInput) (*model.MtgTokenSearch, error) {
+	return mtg.GetMTGTokens(ctx, search, pagination)
+}
+
+// GetMTGDeckTokens is the resolver for the getMTGDeckTokens field.
+func (r *queryResolver) GetMTGDeckTokens(ctx context.Context, deckID string) ([]*model.MtgDeckToken, error) {
+	return mtg.GetMTGDeckTokens(ctx, deckID)
+}
+
 // GetMTGDecks is the resolver for the getMTGDecks field.
 func (r *queryResolver) GetMTGDecks(ctx context.Context) ([]*model.MtgDeckDashboard, error) {
 	return mtg.GetMTGDecks(ctx)
@@ -44,6 +58,11 @@ func (r *queryResolver) GetMTGFilterPresets(ctx context.Context, deckID string)
 	return mtg.GetMTGFilterPresets(ctx, deckID)
 }
 
+// GetMTGPrintingProfiles is the resolver for the getMTGPrintingProfiles field.
+func (r *queryResolver) GetMTGPrintingProfiles(ctx context.Context) ([]*model.MtgPrintingProfile, error) {
+	return mtg.GetMTGPrintingProfiles(ctx)
+}
+
 // GetMTGTags is the resolver for the getMTGTags field.
 func (r *queryResolver) GetMTGTags(ctx context.Context) ([]*model.MtgTag, error) {
 	return mtg.GetMTGTags(ctx)
@@ -75,6 +94,7 @@ func (r *queryResolver) GetMTGImportStatus(ctx context.Context) (*model.MtgImpor
 		daemons.PhaseRebuildingIndex: model.MtgImportPhaseRebuildingIndex,
 		daemons.PhaseComplete:        model.MtgImportPhaseComplete,
 		daemons.PhaseFailed:          model.MtgImportPhaseFailed,
+		daemons.PhaseCancelled:       model.MtgImportPhaseCancelled,
 	}
 
 	phase, ok := phaseMap[status.Phase]
@@ -88,6 +108,21 @@ func (r *queryResolver) GetMTGImportStatus(ctx context.Context) (*model.MtgImpor
 		InProgress: status.InProgress,
 		Phase:      phase,
 		Progress:   status.Progress,
+		Counters: &model.MtgImportCounters{
+			SetsProcessed:      status.Counters.SetsProcessed,
+			BytesDownloaded:    float64(status.Counters.BytesDownloaded),
+			BytesTotal:         float64(status.Counters.BytesTotal),
+			CardsProcessed:     status.Counters.CardsProcessed,
+			BatchesUpserted:    status.Counters.BatchesUpserted,
+			GroupsProcessed:    status.Counters.GroupsProcessed,
+			GroupsTotal:        status.Counters.GroupsTotal,
+			CardsInserted:      status.Counters.CardsInserted,
+			CardsUpdated:       status.Counters.CardsUpdated,
+			CardsRemoved:       status.Counters.CardsRemoved,
+			OriginalsPruned:    status.Counters.OriginalsPruned,
+			RecordsQuarantined: status.Counters.RecordsQuarantined,
+		},
+		EtaSeconds: status.ETASeconds,
 	}
 
 	if status.StartedAt != nil {
@@ -98,13 +133,40 @@ func (r *queryResolver) GetMTGImportStatus(ctx context.Context) (*model.MtgImpor
 		completedAt := status.CompletedAt.Format("2006-01-02T15:04:05Z07:00")
 		result.CompletedAt = &completedAt
 	}
+	if status.NextRun != nil {
+		nextRun := status.NextRun.Format("2006-01-02T15:04:05Z07:00")
+		result.NextScheduledRun = &nextRun
+	}
 	if status.Error != "" {
 		result.Error = &status.Error
 	}
+	if failedPhase, ok := phaseMap[status.FailedPhase]; ok {
+		result.FailedPhase = &failedPhase
+	}
 
 	return result, nil
 }
 
+// GetMTGImportHistory is the resolver for the getMTGImportHistory field.
+func (r *queryResolver) GetMTGImportHistory(ctx context.Context, limit *int) ([]*model.MtgImportRun, error) {
+	return mtg.GetMTGImportHistory(ctx, limit)
+}
+
+// GetMTGCatalogChanges is the resolver for the getMTGCatalogChanges field.
+func (r *queryResolver) GetMTGCatalogChanges(ctx context.Context, since string) ([]*model.MtgCatalogChange, error) {
+	return mtg.GetMTGCatalogChanges(ctx, since)
+}
+
+// GetMTGLegalityChanges is the resolver for the getMTGLegalityChanges field.
+func (r *queryResolver) GetMTGLegalityChanges(ctx context.Context, since string, format *string) ([]*model.MtgLegalityChange, error) {
+	return mtg.GetMTGLegalityChanges(ctx, since, format)
+}
+
+// GetMTGQuarantinedRecords is the resolver for the getMTGQuarantinedRecords field.
+func (r *queryResolver) GetMTGQuarantinedRecords(ctx context.Context, runID *string) ([]*model.MtgQuarantinedRecord, error) {
+	return mtg.GetMTGQuarantinedRecords(ctx, runID)
+}
+
 // Query returns gentypes.QueryResolver implementation.
 func (r *Resolver) Query() gentypes.QueryResolver { return &queryResolver{r} }
 
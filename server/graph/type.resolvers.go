@@ -0,0 +1,51 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.66
+
+import (
+	"context"
+	"magic-helper/graph/gentypes"
+	"magic-helper/graph/model"
+	"magic-helper/graph/mtg"
+)
+
+// Rulings is the resolver for the rulings field.
+func (r *mTG_CardResolver) Rulings(ctx context.Context, obj *model.MtgCard) ([]*model.MtgRuling, error) {
+	// Cards served from the search index already carry their rulings.
+	if obj.Rulings != nil {
+		return obj.Rulings, nil
+	}
+	return mtg.GetMTGCardRulings(ctx, obj.ID)
+}
+
+// RelatedCards is the resolver for the relatedCards field.
+func (r *mTG_CardResolver) RelatedCards(ctx context.Context, obj *model.MtgCard) ([]*model.MtgRelatedCard, error) {
+	return mtg.GetMTGRelatedCards(ctx, obj.ID)
+}
+
+// Prices is the resolver for the prices field.
+func (r *mTG_CardVersionResolver) Prices(ctx context.Context, obj *model.MtgCardVersion) (*model.MtgPrices, error) {
+	// Cards from the search index already carry their latest prices.
+	if obj.Prices != nil {
+		return obj.Prices, nil
+	}
+	return mtg.GetMTGCardVersionPrices(ctx, obj.ID)
+}
+
+// PriceHistory is the resolver for the priceHistory field.
+func (r *mTG_CardVersionResolver) PriceHistory(ctx context.Context, obj *model.MtgCardVersion, days *int) ([]*model.MtgPricePoint, error) {
+	return mtg.GetMTGCardVersionPriceHistory(ctx, obj.ID, days)
+}
+
+// MTG_Card returns gentypes.MTG_CardResolver implementation.
+func (r *Resolver) MTG_Card() gentypes.MTG_CardResolver { return &mTG_CardResolver{r} }
+
+// MTG_CardVersion returns gentypes.MTG_CardVersionResolver implementation.
+func (r *Resolver) MTG_CardVersion() gentypes.MTG_CardVersionResolver {
+	return &mTG_CardVersionResolver{r}
+}
+
+type mTG_CardResolver struct{ *Resolver }
+type mTG_CardVersionResolver struct{ *Resolver }
@@ -7,6 +7,7 @@ import (
 	"magic-helper/daemons"
 	"magic-helper/graph/mtg"
 	"magic-helper/settings"
+	"magic-helper/util/imageCache"
 	"magic-helper/util/logging"
 	"magic-helper/util/mtgCardSearch"
 	"magic-helper/util/muxRouter"
@@ -49,6 +50,11 @@ func main() {
 	// Initialize ArangoDB
 	arango.Init(settings.Current.ArangoDB)
 
+	// Decks saved before savedAt was tracked only report legality changes from now on.
+	if err := mtg.BackfillMTGDeckSavedAt(context.Background()); err != nil {
+		log.Error().Err(err).Msg("Failed to backfill deck savedAt")
+	}
+
 	// Warm up the in-memory search index before serving traffic.
 	if cards, err := mtg.GetMTGCards(context.Background()); err != nil {
 		log.Error().Err(err).Msg("Failed to preload MTG card index")
@@ -58,9 +64,14 @@ func main() {
 		log.Info().Int("cards", len(cards)).Msg("MTG card index preloaded")
 	}
 
-	// Initialize the daemons
-	go daemons.PeriodicFetchMTGSets()
-	go daemons.PeriodicFetchMTGCards()
+	// Open the card image cache behind /image
+	imageCacheSettings := settings.Current.ImageCache
+	if err := imageCache.Init(imageCacheSettings.Dir, imageCacheSettings.MaxSizeMB<<20); err != nil {
+		log.Error().Err(err).Msg("Failed to open image cache, /image redirects to Scryfall")
+	}
+
+	// Initialize the import scheduler
+	go daemons.RunImportScheduler()
 
 	// Start the server
 	log.Info().Msgf("########## Magic Helper Server Startup ##########")
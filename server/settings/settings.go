@@ -24,14 +24,73 @@ type LogConfig struct {
 	LogFilePath string `json:"logFilePath"`
 }
 
+// ImportConfig controls where MTG data is imported from and how it is kept.
+type ImportConfig struct {
+	// CardSource is the provider of sets and cards: "scryfall" (the default) or
+	// "mtgjson".
+	CardSource string `json:"cardSource"`
+	// SetsFile, CardsFile and RulingsFile are local files read instead of
+	// downloading the data; empty downloads it as usual.
+	SetsFile    string `json:"setsFile"`
+	CardsFile   string `json:"cardsFile"`
+	RulingsFile string `json:"rulingsFile"`
+	// Languages lists the printing languages kept in the catalog.
+	Languages []string `json:"languages"`
+	// Schedule is a cron expression in server local time for scheduled imports;
+	// "off" disables them.
+	Schedule string `json:"schedule"`
+	// SetIconDir is the directory set icons are mirrored into.
+	SetIconDir string `json:"setIconDir"`
+	// PriceHistoryDays is how many days of price snapshots are kept.
+	PriceHistoryDays int `json:"priceHistoryDays"`
+	// PruneOriginals is what happens to original printings an import no longer
+	// sees: "archive" (the default) moves them to mtg_original_cards_archive,
+	// "delete" drops them.
+	PruneOriginals string `json:"pruneOriginals"`
+	// ImportDir is the only directory reimportMTGData may read local files
+	// from; empty disables local files in the mutation.
+	ImportDir string `json:"importDir"`
+}
+
+// ScryfallConfig points the importer at the Scryfall API or a compatible mirror:
+// the API base URL, the card bulk dataset to import ("oracle_cards",
+// "default_cards" or "all_cards"; empty picks default_cards, or all_cards when
+// other languages are kept), the User-Agent sent with every request and the
+// minimum pause between two API requests in milliseconds (negative for none).
+type ScryfallConfig struct {
+	BaseURL        string `json:"baseURL"`
+	BulkType       string `json:"bulkType"`
+	UserAgent      string `json:"userAgent"`
+	RequestDelayMs int    `json:"requestDelayMs"`
+}
+
+// MTGJSONConfig points the MTGJSON card source at the MTGJSON API or a mirror
+// serving Meta.json and AllPrintings.json.
+type MTGJSONConfig struct {
+	BaseURL string `json:"baseURL"`
+}
+
+// ImageCacheConfig controls the card image proxy at /image: the directory images
+// are cached in, its size budget, and whether GraphQL responses point image URIs
+// at the proxy instead of Scryfall.
+type ImageCacheConfig struct {
+	Dir              string `json:"dir"`
+	MaxSizeMB        int64  `json:"maxSizeMB"`
+	RewriteImageURIs bool   `json:"rewriteImageURIs"`
+}
+
 // Settings is the main struct that contains the configuration of the application
 type Settings struct {
-	AllowCrossOrigin  bool           `json:"allowCrossOrigin"`
-	Logging           LogConfig      `json:"logging"`
-	Domain            string         `json:"domain"`
-	GraphQLPlayground bool           `json:"graphQLPlayground"`
-	HTTPListen        string         `json:"httpListen"`
-	ArangoDB          ArangoDBConfig `json:"arangoDB"`
+	AllowCrossOrigin  bool             `json:"allowCrossOrigin"`
+	Logging           LogConfig        `json:"logging"`
+	Domain            string           `json:"domain"`
+	GraphQLPlayground bool             `json:"graphQLPlayground"`
+	HTTPListen        string           `json:"httpListen"`
+	ArangoDB          ArangoDBConfig   `json:"arangoDB"`
+	Import            ImportConfig     `json:"import"`
+	Scryfall          ScryfallConfig   `json:"scryfall"`
+	MTGJSON           MTGJSONConfig    `json:"mtgjson"`
+	ImageCache        ImageCacheConfig `json:"imageCache"`
 }
 
 // Current holds the process-wide active configuration.
@@ -89,6 +148,63 @@ func loadSettingsFromFile(settingsFile string) {
 	if isEmpty(newSettings.Logging.LogLevel) {
 		newSettings.Logging.LogLevel = "Info"
 	}
+	if len(newSettings.Import.Languages) == 0 {
+		newSettings.Import.Languages = []string{"en"}
+	}
+	if isEmpty(newSettings.Import.Schedule) {
+		newSettings.Import.Schedule = "0 4 * * *"
+	}
+	if isEmpty(newSettings.Import.SetIconDir) {
+		newSettings.Import.SetIconDir = "public/images/sets"
+	}
+	if newSettings.Import.PriceHistoryDays <= 0 {
+		newSettings.Import.PriceHistoryDays = 365
+	}
+	newSettings.Import.PruneOriginals = strings.TrimSpace(newSettings.Import.PruneOriginals)
+	switch newSettings.Import.PruneOriginals {
+	case "":
+		newSettings.Import.PruneOriginals = "archive"
+	case "archive", "delete":
+	default:
+		log.Fatal().Str("pruneOriginals", newSettings.Import.PruneOriginals).Msg("error: import.pruneOriginals must be archive or delete")
+	}
+	if isEmpty(newSettings.Scryfall.BaseURL) {
+		newSettings.Scryfall.BaseURL = "https://api.scryfall.com"
+	}
+	newSettings.Scryfall.BaseURL = strings.TrimRight(strings.TrimSpace(newSettings.Scryfall.BaseURL), "/")
+	newSettings.Scryfall.BulkType = strings.TrimSpace(newSettings.Scryfall.BulkType)
+	switch newSettings.Scryfall.BulkType {
+	case "", "oracle_cards", "default_cards", "all_cards":
+	default:
+		log.Fatal().Str("bulkType", newSettings.Scryfall.BulkType).Msg("error: scryfall.bulkType must be oracle_cards, default_cards or all_cards")
+	}
+	if newSettings.Scryfall.BulkType != "" && newSettings.Scryfall.BulkType != "all_cards" && len(newSettings.Import.Languages) > 1 {
+		log.Warn().Str("bulkType", newSettings.Scryfall.BulkType).Msg("scryfall.bulkType has no localized printings, only English ones will be imported")
+	}
+	if isEmpty(newSettings.Scryfall.UserAgent) {
+		newSettings.Scryfall.UserAgent = "MagicHelper/0.1"
+	}
+	if newSettings.Scryfall.RequestDelayMs == 0 {
+		newSettings.Scryfall.RequestDelayMs = 100
+	}
+	newSettings.Import.CardSource = strings.ToLower(strings.TrimSpace(newSettings.Import.CardSource))
+	switch newSettings.Import.CardSource {
+	case "":
+		newSettings.Import.CardSource = "scryfall"
+	case "scryfall", "mtgjson":
+	default:
+		log.Fatal().Str("cardSource", newSettings.Import.CardSource).Msg("error: import.cardSource must be scryfall or mtgjson")
+	}
+	if isEmpty(newSettings.MTGJSON.BaseURL) {
+		newSettings.MTGJSON.BaseURL = "https://mtgjson.com/api/v5"
+	}
+	newSettings.MTGJSON.BaseURL = strings.TrimRight(strings.TrimSpace(newSettings.MTGJSON.BaseURL), "/")
+	if isEmpty(newSettings.ImageCache.Dir) {
+		newSettings.ImageCache.Dir = "images"
+	}
+	if newSettings.ImageCache.MaxSizeMB <= 0 {
+		newSettings.ImageCache.MaxSizeMB = 2048
+	}
 
 	Current = newSettings
 }
@@ -0,0 +1,233 @@
+package imageCache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Cache keeps downloaded images in a directory under a size budget, evicting the
+// least recently used files first. Recency survives restarts through the files'
+// modification times, which are bumped on every hit.
+type Cache struct {
+	dir      string
+	maxBytes int64
+
+	mu       sync.Mutex
+	lru      *list.List               // front is the most recently used entry
+	entries  map[string]*list.Element // key -> element holding an *entry
+	size     int64
+	inflight map[string]*fetchCall
+}
+
+type entry struct {
+	key  string
+	size int64
+}
+
+// fetchCall lets concurrent requests for the same missing key share one download.
+type fetchCall struct {
+	done chan struct{}
+	err  error
+}
+
+var current *Cache
+
+// Init opens the image cache in dir with a budget of maxBytes and makes it the
+// process-wide cache returned by GetImageCache.
+func Init(dir string, maxBytes int64) error {
+	cache, err := New(dir, maxBytes)
+	if err != nil {
+		return err
+	}
+	current = cache
+	return nil
+}
+
+// GetImageCache returns the process-wide cache, nil when Init failed or was not called.
+func GetImageCache() *Cache {
+	return current
+}
+
+// New opens the cache in dir, creating it when missing, and indexes the files
+// already there from least to most recently used.
+func New(dir string, maxBytes int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  map[string]*list.Element{},
+		inflight: map[string]*fetchCall{},
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type cachedFile struct {
+		key     string
+		size    int64
+		modTime time.Time
+	}
+	var cached []cachedFile
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		cached = append(cached, cachedFile{key: file.Name(), size: info.Size(), modTime: info.ModTime()})
+	}
+	sort.Slice(cached, func(i, j int) bool { return cached[i].modTime.Before(cached[j].modTime) })
+
+	c.mu.Lock()
+	for _, file := range cached {
+		c.entries[file.key] = c.lru.PushFront(&entry{key: file.key, size: file.size})
+		c.size += file.size
+	}
+	c.evict()
+	c.mu.Unlock()
+
+	log.Info().Str("dir", dir).Int("images", c.lru.Len()).Int64("bytes", c.size).Int64("maxBytes", maxBytes).Msg("Image cache loaded")
+	return c, nil
+}
+
+// Open returns the cached file for key, calling fetch to download it first when
+// it is not cached. Concurrent calls for the same key share a single fetch. The
+// caller must close the returned file. key is used as the file name and must
+// not contain path separators.
+func (c *Cache) Open(ctx context.Context, key string, fetch func(ctx context.Context) (io.ReadCloser, error)) (*os.File, error) {
+	if key == "" || strings.ContainsAny(key, `/\`) || strings.HasPrefix(key, ".") {
+		return nil, fmt.Errorf("invalid image cache key %q", key)
+	}
+	path := filepath.Join(c.dir, key)
+
+	for {
+		c.mu.Lock()
+		if elem, ok := c.entries[key]; ok {
+			c.lru.MoveToFront(elem)
+			c.mu.Unlock()
+
+			file, err := os.Open(path)
+			if err == nil {
+				now := time.Now()
+				_ = os.Chtimes(path, now, now)
+				return file, nil
+			}
+			// The file vanished from disk; forget it and download it again.
+			c.mu.Lock()
+			if elem, ok := c.entries[key]; ok {
+				c.remove(elem, false)
+			}
+			c.mu.Unlock()
+			continue
+		}
+
+		if call, ok := c.inflight[key]; ok {
+			c.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-call.done:
+			}
+			// Retry when the request that started the download was cancelled.
+			if call.err != nil && !errors.Is(call.err, context.Canceled) {
+				return nil, call.err
+			}
+			continue
+		}
+
+		call := &fetchCall{done: make(chan struct{})}
+		c.inflight[key] = call
+		c.mu.Unlock()
+
+		size, err := c.download(ctx, path, fetch)
+
+		c.mu.Lock()
+		if err == nil {
+			c.entries[key] = c.lru.PushFront(&entry{key: key, size: size})
+			c.size += size
+			c.evict()
+		}
+		delete(c.inflight, key)
+		call.err = err
+		close(call.done)
+		c.mu.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// download streams fetch's body to a temporary file and renames it to path, so a
+// failed or cancelled download never leaves a partial image in the cache.
+func (c *Cache) download(ctx context.Context, path string, fetch func(ctx context.Context) (io.ReadCloser, error)) (int64, error) {
+	body, err := fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp(c.dir, ".download-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	if size == 0 {
+		return 0, errors.New("empty image")
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// evict removes least recently used files until the cache fits its budget. The
+// most recent entry is always kept so an image larger than the budget can still
+// be served. c.mu must be held.
+func (c *Cache) evict() {
+	for c.size > c.maxBytes && c.lru.Len() > 1 {
+		c.remove(c.lru.Back(), true)
+	}
+}
+
+// remove drops elem from the index and, with deleteFile, from disk. c.mu must be held.
+func (c *Cache) remove(elem *list.Element, deleteFile bool) {
+	e := elem.Value.(*entry)
+	c.lru.Remove(elem)
+	delete(c.entries, e.key)
+	c.size -= e.size
+	if deleteFile {
+		if err := os.Remove(filepath.Join(c.dir, e.key)); err != nil && !os.IsNotExist(err) {
+			log.Warn().Err(err).Str("image", e.key).Msg("Error evicting cached image")
+		}
+	}
+}
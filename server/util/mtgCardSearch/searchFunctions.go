@@ -58,7 +58,7 @@ func FilterCardsWithPagination(cards []*model.MtgCard, filter model.MtgFilterSea
 		}
 	}
 
-	compare := buildCompare(sortInputs, filter.HideUnreleased, filter.Games)
+	compare := buildCompare(sortInputs, filter.HideUnreleased, filter.Games, priceCurrency(filter.Price))
 	K := (pagination.Page + 1) * pagination.PageSize
 	if pagination.PageSize <= 0 {
 		K = 0
@@ -144,10 +144,11 @@ func (h *cardHeap) Pop() any {
 }
 
 // buildCompare returns a comparator: -1 if a before b, 1 if a after b, 0 if equal.
-// Uses sortInputs for multi-level comparison and always ends with a.ID vs b.ID for total order.
+// Uses sortInputs for multi-level comparison, then falls back to the name (card keys are
+// oracle IDs, not names) and always ends with a.ID vs b.ID for total order.
 // When hideUnreleased is true, release-date sorting uses only released versions.
 // When gamesFilter is non-empty, release/rarity/set sorting use only effective versions (matching games filter).
-func buildCompare(sortInputs []*model.MtgFilterSortInput, hideUnreleased bool, gamesFilter []*model.MtgFilterGameInput) func(a, b *model.MtgCard) int {
+func buildCompare(sortInputs []*model.MtgFilterSortInput, hideUnreleased bool, gamesFilter []*model.MtgFilterGameInput, currency model.MtgPriceCurrency) func(a, b *model.MtgCard) int {
 	enabled := make([]*model.MtgFilterSortInput, 0)
 	for _, s := range sortInputs {
 		if s != nil && s.Enabled {
@@ -156,11 +157,14 @@ func buildCompare(sortInputs []*model.MtgFilterSortInput, hideUnreleased bool, g
 	}
 	return func(a, b *model.MtgCard) int {
 		for _, level := range enabled {
-			cmp := compareBySortCriteria(a, b, level, hideUnreleased, gamesFilter)
+			cmp := compareBySortCriteria(a, b, level, hideUnreleased, gamesFilter, currency)
 			if cmp != 0 {
 				return cmp
 			}
 		}
+		if a.Name != b.Name {
+			return strings.Compare(a.Name, b.Name)
+		}
 		if a.ID < b.ID {
 			return -1
 		}
@@ -270,6 +274,14 @@ func containsString(items []string, target string) bool {
 	return false
 }
 
+// derefString returns the pointed-to string, or "" for nil.
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
 // PaginateCards slices the input according to page and pageSize.
 func PaginateCards(cards []*model.MtgCard, pagination model.MtgFilterPaginationInput) []*model.MtgCard {
 	// Convert page and pageSize to offset and limit
@@ -363,6 +375,21 @@ func passesFilter(card *model.MtgCard, filter model.MtgFilterSearchInput, ignore
 		return false
 	}
 
+	// Price filtering (cheapest effective version)
+	if !passesPriceFilter(filter.Price, versions) {
+		return false
+	}
+
+	// Game Changer, Reserved List, defense and Penny Dreadful rank filtering
+	if !passesCardAttributeFilter(card, filter) {
+		return false
+	}
+
+	// Printing attribute filtering (over effective versions)
+	if !passesPrintingFilter(filter, versions) {
+		return false
+	}
+
 	// Card type filtering
 	if !passesCardTypeFilter(card, filter.CardTypes) {
 		return false
@@ -383,6 +410,11 @@ func passesFilter(card *model.MtgCard, filter model.MtgFilterSearchInput, ignore
 		return false
 	}
 
+	// Language filtering: at least one printing in an allowed language
+	if !passesLanguageFilter(card, filter.Languages) {
+		return false
+	}
+
 	// Hide unreleased: at least one effective version must have ReleasedAt <= today
 	if filter.HideUnreleased {
 		if !cardHasReleasedVersionFromVersions(versions) {
@@ -500,6 +532,25 @@ func passesSearchString(card *model.MtgCard, searchString string) bool {
 			if searchValue, ok := query.Value.(string); ok {
 				searchLower := strings.ToLower(searchValue)
 				matches := card.OracleText != nil && strings.Contains(strings.ToLower(*card.OracleText), searchLower)
+				if !matches {
+					matches = printedTextMatches(card, searchLower)
+				}
+
+				if (query.Not && matches) || (!query.Not && !matches) {
+					return false
+				}
+			}
+
+		case QueryTypeRuling:
+			if searchValue, ok := query.Value.(string); ok {
+				searchLower := strings.ToLower(searchValue)
+				matches := false
+				for _, ruling := range card.Rulings {
+					if ruling != nil && strings.Contains(strings.ToLower(ruling.Comment), searchLower) {
+						matches = true
+						break
+					}
+				}
 
 				if (query.Not && matches) || (!query.Not && !matches) {
 					return false
@@ -524,6 +575,20 @@ func passesSearchString(card *model.MtgCard, searchString string) bool {
 				}
 			}
 
+		case QueryTypeLanguage:
+			if langValue, ok := query.Value.(string); ok {
+				hasLang := false
+				for _, version := range card.Versions {
+					if version != nil && strings.EqualFold(version.Lang, langValue) {
+						hasLang = true
+						break
+					}
+				}
+				if (query.Not && hasLang) || (!query.Not && !hasLang) {
+					return false
+				}
+			}
+
 		case QueryTypeSearch:
 			if searchValue, ok := query.Value.(string); ok {
 				searchLower := strings.ToLower(searchValue)
@@ -559,6 +624,9 @@ func defaultSearchMatches(card *model.MtgCard, searchLower string) bool {
 		if v.FlavorText != nil && strings.Contains(strings.ToLower(*v.FlavorText), searchLower) {
 			return true
 		}
+		if localizedVersionMatches(v, searchLower) {
+			return true
+		}
 		for _, f := range v.CardFaces {
 			if f == nil {
 				continue
@@ -580,6 +648,328 @@ func defaultSearchMatches(card *model.MtgCard, searchLower string) bool {
 	return false
 }
 
+// localizedVersionMatches checks the localized name, type line and text printed on a
+// non-English version and its faces.
+func localizedVersionMatches(v *model.MtgCardVersion, searchLower string) bool {
+	if strings.EqualFold(v.Lang, "en") {
+		return false
+	}
+	if strings.Contains(strings.ToLower(v.PrintedName), searchLower) {
+		return true
+	}
+	if v.PrintedTypeLine != nil && strings.Contains(strings.ToLower(*v.PrintedTypeLine), searchLower) {
+		return true
+	}
+	if v.PrintedText != nil && strings.Contains(strings.ToLower(*v.PrintedText), searchLower) {
+		return true
+	}
+	for _, f := range v.CardFaces {
+		if f == nil {
+			continue
+		}
+		if f.PrintedName != nil && strings.Contains(strings.ToLower(*f.PrintedName), searchLower) {
+			return true
+		}
+		if f.PrintedTypeLine != nil && strings.Contains(strings.ToLower(*f.PrintedTypeLine), searchLower) {
+			return true
+		}
+		if f.PrintedText != nil && strings.Contains(strings.ToLower(*f.PrintedText), searchLower) {
+			return true
+		}
+	}
+	return false
+}
+
+// printedTextMatches checks the localized rules text of every version and face.
+func printedTextMatches(card *model.MtgCard, searchLower string) bool {
+	for _, v := range card.Versions {
+		if v == nil {
+			continue
+		}
+		if v.PrintedText != nil && strings.Contains(strings.ToLower(*v.PrintedText), searchLower) {
+			return true
+		}
+		for _, f := range v.CardFaces {
+			if f != nil && f.PrintedText != nil && strings.Contains(strings.ToLower(*f.PrintedText), searchLower) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// passesLanguageFilter checks printing languages with ternary entries: the card needs at
+// least one version that is not in a FALSE language and, when TRUE languages are given,
+// is in one of them.
+func passesLanguageFilter(card *model.MtgCard, languageFilters []*model.MtgFilterLanguageInput) bool {
+	if len(languageFilters) == 0 {
+		return true
+	}
+	positive := make(map[string]struct{})
+	negative := make(map[string]struct{})
+	for _, entry := range languageFilters {
+		if entry == nil {
+			continue
+		}
+		switch entry.Value {
+		case model.TernaryBooleanTrue:
+			positive[strings.ToLower(entry.Lang)] = struct{}{}
+		case model.TernaryBooleanFalse:
+			negative[strings.ToLower(entry.Lang)] = struct{}{}
+		}
+	}
+	if len(positive) == 0 && len(negative) == 0 {
+		return true
+	}
+	for _, v := range card.Versions {
+		if v == nil {
+			continue
+		}
+		lang := strings.ToLower(v.Lang)
+		if _, excluded := negative[lang]; excluded {
+			continue
+		}
+		if len(positive) > 0 {
+			if _, included := positive[lang]; !included {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// passesPriceFilter checks that the cheapest of the versions, in any finish, lies
+// within the requested range. Cards without a price in the currency never match.
+func passesPriceFilter(priceFilter *model.MtgFilterPriceInput, versions []*model.MtgCardVersion) bool {
+	if priceFilter == nil || (priceFilter.Min == nil && priceFilter.Max == nil) {
+		return true
+	}
+	price, ok := cheapestPrice(versions, priceFilter.Currency)
+	if !ok {
+		return false
+	}
+	if priceFilter.Min != nil && price < *priceFilter.Min {
+		return false
+	}
+	if priceFilter.Max != nil && price > *priceFilter.Max {
+		return false
+	}
+	return true
+}
+
+// priceCurrency returns the currency prices are compared in: the price filter's,
+// or USD without one.
+func priceCurrency(priceFilter *model.MtgFilterPriceInput) model.MtgPriceCurrency {
+	if priceFilter != nil && priceFilter.Currency.IsValid() {
+		return priceFilter.Currency
+	}
+	return model.MtgPriceCurrencyUsd
+}
+
+// cheapestPrice returns the lowest price in currency over all finishes of the
+// versions, and false when none of them has one.
+func cheapestPrice(versions []*model.MtgCardVersion, currency model.MtgPriceCurrency) (float64, bool) {
+	cheapest, found := 0.0, false
+	for _, v := range versions {
+		if v == nil || v.Prices == nil {
+			continue
+		}
+		var candidates []*float64
+		switch currency {
+		case model.MtgPriceCurrencyEur:
+			candidates = []*float64{v.Prices.Eur, v.Prices.EurFoil, v.Prices.EurEtched}
+		case model.MtgPriceCurrencyTix:
+			candidates = []*float64{v.Prices.Tix}
+		default:
+			candidates = []*float64{v.Prices.Usd, v.Prices.UsdFoil, v.Prices.UsdEtched}
+		}
+		for _, price := range candidates {
+			if price != nil && (!found || *price < cheapest) {
+				cheapest, found = *price, true
+			}
+		}
+	}
+	return cheapest, found
+}
+
+// passesCardAttributeFilter checks the card-level flags and ranges: Game Changer and
+// Reserved List membership, battle defense and Penny Dreadful rank.
+func passesCardAttributeFilter(card *model.MtgCard, filter model.MtgFilterSearchInput) bool {
+	if !passesTernaryFlag(filter.GameChanger, card.GameChanger) {
+		return false
+	}
+	if !passesTernaryFlag(filter.Reserved, card.Reserved) {
+		return false
+	}
+	defense, ok := cardDefense(card)
+	if !passesRangeFilter(filter.Defense, defense, ok) {
+		return false
+	}
+	pennyRank, ok := 0, card.PennyRank != nil
+	if ok {
+		pennyRank = *card.PennyRank
+	}
+	return passesRangeFilter(filter.PennyRank, pennyRank, ok)
+}
+
+// passesPrintingFilter checks the printing attributes (border color, frame, watermark,
+// security stamp, promo, digital, textless and catalog IDs). A card matches when one of
+// the versions satisfies all of them at once.
+func passesPrintingFilter(filter model.MtgFilterSearchInput, versions []*model.MtgCardVersion) bool {
+	borderColors := collectTernaryValues(filter.BorderColors, func(e *model.MtgFilterBorderColorInput) (string, model.TernaryBoolean) {
+		return e.BorderColor, e.Value
+	})
+	frames := collectTernaryValues(filter.Frames, func(e *model.MtgFilterFrameInput) (string, model.TernaryBoolean) {
+		return e.Frame, e.Value
+	})
+	watermarks := collectTernaryValues(filter.Watermarks, func(e *model.MtgFilterWatermarkInput) (string, model.TernaryBoolean) {
+		return e.Watermark, e.Value
+	})
+	securityStamps := collectTernaryValues(filter.SecurityStamps, func(e *model.MtgFilterSecurityStampInput) (string, model.TernaryBoolean) {
+		return e.SecurityStamp, e.Value
+	})
+	if borderColors.empty() && frames.empty() && watermarks.empty() && securityStamps.empty() &&
+		filter.Promo == nil && filter.Digital == nil && filter.Textless == nil && filter.ExternalIDs == nil {
+		return true
+	}
+
+	for _, v := range versions {
+		if v == nil {
+			continue
+		}
+		if !borderColors.matches(v.BorderColor) || !frames.matches(v.Frame) ||
+			!watermarks.matches(derefString(v.Watermark)) || !securityStamps.matches(derefString(v.SecurityStamp)) {
+			continue
+		}
+		if !passesTernaryFlag(filter.Promo, v.Promo) || !passesTernaryFlag(filter.Digital, v.Digital) ||
+			!passesTernaryFlag(filter.Textless, v.Textless) {
+			continue
+		}
+		if !matchesExternalIDs(filter.ExternalIDs, v) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// ternaryValues holds the lowercased values of ternary filter entries by state.
+type ternaryValues struct {
+	positive map[string]struct{}
+	negative map[string]struct{}
+}
+
+// collectTernaryValues splits ternary filter entries into TRUE and FALSE values.
+func collectTernaryValues[T any](entries []*T, entry func(*T) (string, model.TernaryBoolean)) ternaryValues {
+	values := ternaryValues{positive: map[string]struct{}{}, negative: map[string]struct{}{}}
+	for _, e := range entries {
+		if e == nil {
+			continue
+		}
+		value, state := entry(e)
+		switch state {
+		case model.TernaryBooleanTrue:
+			values.positive[strings.ToLower(value)] = struct{}{}
+		case model.TernaryBooleanFalse:
+			values.negative[strings.ToLower(value)] = struct{}{}
+		}
+	}
+	return values
+}
+
+func (t ternaryValues) empty() bool {
+	return len(t.positive) == 0 && len(t.negative) == 0
+}
+
+// matches reports whether value is not excluded and, when TRUE values are given, is one of them.
+func (t ternaryValues) matches(value string) bool {
+	value = strings.ToLower(value)
+	if _, excluded := t.negative[value]; excluded {
+		return false
+	}
+	if len(t.positive) > 0 {
+		_, included := t.positive[value]
+		return included
+	}
+	return true
+}
+
+// passesTernaryFlag checks a boolean against a ternary filter: TRUE requires it to be set,
+// FALSE requires it to be unset, nil or UNSET accepts both.
+func passesTernaryFlag(value *model.TernaryBoolean, flag bool) bool {
+	if value == nil {
+		return true
+	}
+	switch *value {
+	case model.TernaryBooleanTrue:
+		return flag
+	case model.TernaryBooleanFalse:
+		return !flag
+	}
+	return true
+}
+
+// passesRangeFilter checks value against an inclusive range. ok is false when the card has
+// no value, which never matches a range with a bound.
+func passesRangeFilter(rangeFilter *model.MtgFilterRangeInput, value int, ok bool) bool {
+	if rangeFilter == nil || (rangeFilter.Min == nil && rangeFilter.Max == nil) {
+		return true
+	}
+	if !ok {
+		return false
+	}
+	if rangeFilter.Min != nil && value < *rangeFilter.Min {
+		return false
+	}
+	if rangeFilter.Max != nil && value > *rangeFilter.Max {
+		return false
+	}
+	return true
+}
+
+// cardDefense returns the numeric defense of a battle, taken from the card or, for
+// double-faced battles, from the first face that has one.
+func cardDefense(card *model.MtgCard) (int, bool) {
+	defense := card.Defense
+	if defense == nil {
+		for _, v := range card.Versions {
+			if v == nil {
+				continue
+			}
+			for _, face := range v.CardFaces {
+				if face != nil && face.Defense != nil {
+					defense = face.Defense
+					break
+				}
+			}
+			break
+		}
+	}
+	if defense == nil {
+		return 0, false
+	}
+	value, err := strconv.Atoi(*defense)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// matchesExternalIDs reports whether the version carries every catalog ID of the filter.
+func matchesExternalIDs(ids *model.MtgFilterExternalIDInput, v *model.MtgCardVersion) bool {
+	if ids == nil {
+		return true
+	}
+	return sameID(ids.ArenaID, v.ArenaID) && sameID(ids.MtgoID, v.MtgoID) &&
+		sameID(ids.TcgplayerID, v.TcgplayerID) && sameID(ids.CardmarketID, v.CardmarketID)
+}
+
+// sameID reports whether want is unset or equal to got.
+func sameID(want, got *int) bool {
+	return want == nil || (got != nil && *want == *got)
+}
+
 // passesColorFilter checks if a card passes the color filtering criteria.
 func passesColorFilter(card *model.MtgCard, colorFilters []*model.MtgFilterColorInput, multiColor model.TernaryBoolean) bool {
 	if len(colorFilters) == 0 && multiColor == model.TernaryBooleanUnset {
@@ -1205,7 +1595,7 @@ func buildChainKeyFromFilter(terminalTagID string, chainTagIDs []string) string
 // Returns: -1 if cardA < cardB, 1 if cardA > cardB, 0 if equal.
 // When hideUnreleased is true, release-date and set sorting use only released versions.
 // When gamesFilter is non-empty, release/rarity/set use only effective versions.
-func compareBySortCriteria(cardA, cardB *model.MtgCard, sortCriteria *model.MtgFilterSortInput, hideUnreleased bool, gamesFilter []*model.MtgFilterGameInput) int {
+func compareBySortCriteria(cardA, cardB *model.MtgCard, sortCriteria *model.MtgFilterSortInput, hideUnreleased bool, gamesFilter []*model.MtgFilterGameInput, currency model.MtgPriceCurrency) int {
 	isDesc := sortCriteria.SortDirection == model.MtgFilterSortDirectionDesc
 
 	var comparison int
@@ -1269,6 +1659,24 @@ func compareBySortCriteria(cardA, cardB *model.MtgCard, sortCriteria *model.MtgF
 			comparison = 0
 		}
 
+	case model.MtgFilterSortByPrice:
+		priceA, okA := cheapestPrice(versionsToUse(cardA, gamesFilter), currency)
+		priceB, okB := cheapestPrice(versionsToUse(cardB, gamesFilter), currency)
+		// Cards without a price go last in both directions.
+		if okA != okB {
+			if okA {
+				return -1
+			}
+			return 1
+		}
+		if priceA < priceB {
+			comparison = -1
+		} else if priceA > priceB {
+			comparison = 1
+		} else {
+			comparison = 0
+		}
+
 	default:
 		comparison = 0
 	}
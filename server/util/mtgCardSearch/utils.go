@@ -30,6 +30,8 @@ const (
 	QueryTypeColor      QueryType = "Color"
 	QueryTypeOracle     QueryType = "Oracle"
 	QueryTypeFlavorText QueryType = "FlavorText"
+	QueryTypeLanguage   QueryType = "Language"
+	QueryTypeRuling     QueryType = "Ruling"
 )
 
 // Query represents a parsed query with its type, value, and negation flag.
@@ -295,6 +297,40 @@ func CalculateQuery(s string) Query {
 	// Normalize input by converting to lowercase
 	s = strings.ToLower(s)
 
+	// Handle language queries (lang:) before the shorter prefixes below
+	if strings.Contains(s, "lang:") {
+		parts := strings.Split(s, "lang:")
+		if len(parts) > 1 {
+			q := strings.TrimSpace(parts[1])
+			not := strings.HasPrefix(q, "!")
+			if not {
+				q = q[1:]
+			}
+			return Query{
+				Type:  QueryTypeLanguage,
+				Value: q,
+				Not:   not,
+			}
+		}
+	}
+
+	// Handle ruling text queries (ruling:)
+	if strings.Contains(s, "ruling:") {
+		parts := strings.Split(s, "ruling:")
+		if len(parts) > 1 {
+			q := strings.TrimSpace(parts[1])
+			not := strings.HasPrefix(q, "!")
+			if not {
+				q = q[1:]
+			}
+			return Query{
+				Type:  QueryTypeRuling,
+				Value: q,
+				Not:   not,
+			}
+		}
+	}
+
 	// Handle set queries (set: and s:)
 	if strings.Contains(s, "set:") || strings.Contains(s, "s:") {
 		var parts []string
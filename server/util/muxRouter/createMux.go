@@ -30,6 +30,10 @@ func CreateMux(settings settings.Settings) *mux.Router {
 	graphQLServer.AddTransport(transport.GET{})
 	graphQLServer.AddTransport(transport.POST{})
 
+	if settings.ImageCache.RewriteImageURIs {
+		graphQLServer.AroundFields(rewriteImageURIs)
+	}
+
 	if settings.GraphQLPlayground {
 		router.Handle("/playground", playground.Handler("GraphQL playground", "/graphql"))
 	}
@@ -42,6 +46,9 @@ func CreateMux(settings settings.Settings) *mux.Router {
 	// Serve the set images
 	router.HandleFunc("/set/{code}", setHandler)
 
+	// Serve card images through the local image cache
+	router.HandleFunc("/image/{versionID}/{size}", imageHandler).Methods("GET", "HEAD")
+
 	// router.Handle("/graphql-admin", auth.AuthGraphQLAdminHandler(graphQLServer))
 	// router.Handle("/graphql-private", auth.AuthGraphQLPrivateHandler(graphQLServer))
 	router.Handle("/graphql", Handler(graphQLServer))
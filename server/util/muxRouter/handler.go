@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"magic-helper/settings"
 	"net/http"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
 )
 
@@ -47,19 +52,43 @@ func Handler(next http.Handler) http.Handler {
 	})
 }
 
-// setHandler serves an SVG for a set code from the public images directory.
+// genericSetIcon is served for sets whose icon has not been mirrored (yet).
+const genericSetIcon = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 32 32"><path d="M16 2 30 16 16 30 2 16Z" fill="none" stroke="currentColor" stroke-width="3"/></svg>`
+
+// setCodePattern matches Scryfall set codes and keeps paths inside the icon directory.
+var setCodePattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// setHandler serves the mirrored SVG icon for a set code from the configured
+// set icon directory, falling back to a generic icon. Mirrored icons are cached
+// for a day and revalidated with Last-Modified; the fallback only for an hour so
+// a newly mirrored icon shows up soon.
 func setHandler(w http.ResponseWriter, r *http.Request) {
-	log.Info().Msg("Serving set image")
-	setName := r.URL.Path[len("/set/"):]
-	log.Info().Msgf("Set name: %v", setName)
-	if setName == "" {
+	code := strings.ToLower(strings.TrimSuffix(mux.Vars(r)["code"], ".svg"))
+	if !setCodePattern.MatchString(code) {
 		http.Error(w, "Bad Request", http.StatusBadRequest)
 		return
 	}
 
-	filePath := filepath.Join("public", "images", "sets", setName+".svg")
-
 	w.Header().Set("Content-Type", "image/svg+xml")
 
-	http.ServeFile(w, r, filePath)
+	file, err := os.Open(filepath.Join(settings.Current.Import.SetIconDir, code+".svg"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Error().Err(err).Str("set", code).Msg("error opening set icon")
+		}
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		http.ServeContent(w, r, "generic.svg", time.Time{}, strings.NewReader(genericSetIcon))
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		log.Error().Err(err).Str("set", code).Msg("error reading set icon")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), file)
 }
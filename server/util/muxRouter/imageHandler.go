@@ -0,0 +1,229 @@
+package muxRouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"magic-helper/graph/model"
+	"magic-helper/graph/mtg"
+	"magic-helper/settings"
+	"magic-helper/util/imageCache"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog/log"
+)
+
+// imageHTTPClient downloads card images from Scryfall's CDN.
+var imageHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// versionIDPattern matches Scryfall card IDs.
+var versionIDPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// imageVersionPattern matches the version stamp Scryfall appends to image URIs.
+var imageVersionPattern = regexp.MustCompile(`^[0-9]+$`)
+
+// imageSizes maps the Scryfall image sizes accepted by /image to the matching
+// MTG_Image URI.
+var imageSizes = map[string]func(image *model.MtgImage) string{
+	"small":       func(image *model.MtgImage) string { return image.Small },
+	"normal":      func(image *model.MtgImage) string { return image.Normal },
+	"large":       func(image *model.MtgImage) string { return image.Large },
+	"png":         func(image *model.MtgImage) string { return image.Png },
+	"art_crop":    func(image *model.MtgImage) string { return image.ArtCrop },
+	"border_crop": func(image *model.MtgImage) string { return image.BorderCrop },
+}
+
+// imageHandler serves /image/{versionID}/{size} from the local image cache,
+// downloading the Scryfall image of that card version on first use. The optional
+// face=back query selects the back face of double-faced printings, and v (the
+// version stamp of the Scryfall URI) keys the cache so a replaced scan is fetched
+// again. Images are served with a long-lived Cache-Control header.
+func imageHandler(w http.ResponseWriter, r *http.Request) {
+	versionID := strings.ToLower(mux.Vars(r)["versionID"])
+	size := strings.ToLower(mux.Vars(r)["size"])
+	face := r.URL.Query().Get("face")
+	version := r.URL.Query().Get("v")
+
+	sizeURI, ok := imageSizes[size]
+	if !versionIDPattern.MatchString(versionID) || !ok || (face != "" && face != "front" && face != "back") || (version != "" && !imageVersionPattern.MatchString(version)) {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	if face == "" {
+		face = "front"
+	}
+
+	// The source URI is only needed on a cache miss, so it is looked up lazily.
+	var sourceURI string
+	lookup := func(ctx context.Context) (string, error) {
+		if sourceURI != "" {
+			return sourceURI, nil
+		}
+		images, err := mtg.GetMTGCardVersionImages(ctx, versionID)
+		if err != nil {
+			return "", err
+		}
+		sourceURI = versionImageURI(images, face, sizeURI)
+		if sourceURI == "" {
+			return "", errImageNotFound
+		}
+		return sourceURI, nil
+	}
+
+	cache := imageCache.GetImageCache()
+	if cache == nil {
+		uri, err := lookup(r.Context())
+		if err != nil {
+			writeImageError(w, versionID, err)
+			return
+		}
+		http.Redirect(w, r, uri, http.StatusFound)
+		return
+	}
+
+	key := fmt.Sprintf("%s-%s-%s", versionID, face, size)
+	if version != "" {
+		key += "-" + version
+	}
+	key += imageExtension(size)
+
+	file, err := cache.Open(r.Context(), key, func(ctx context.Context) (io.ReadCloser, error) {
+		uri, err := lookup(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return downloadImage(ctx, uri)
+	})
+	if err != nil {
+		writeImageError(w, versionID, err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		log.Error().Err(err).Str("image", key).Msg("error reading cached image")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=31536000")
+	http.ServeContent(w, r, key, info.ModTime(), file)
+}
+
+// errImageNotFound reports a version without an image of the requested size.
+var errImageNotFound = errors.New("image not found")
+
+// writeImageError answers a failed image request: 404 for unknown versions or
+// sizes, 502 when Scryfall could not be reached.
+func writeImageError(w http.ResponseWriter, versionID string, err error) {
+	if errors.Is(err, errImageNotFound) {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+	log.Warn().Err(err).Str("versionID", versionID).Msg("error fetching card image")
+	http.Error(w, "Bad Gateway", http.StatusBadGateway)
+}
+
+// versionImageURI picks the URI of the requested face and size: the version's own
+// image for the front, falling back to the faces of multi-faced printings.
+func versionImageURI(images *mtg.MTGCardVersionImages, face string, sizeURI func(image *model.MtgImage) string) string {
+	if images == nil {
+		return ""
+	}
+	if face == "front" && images.ImageUris != nil {
+		return sizeURI(images.ImageUris)
+	}
+	index := 0
+	if face == "back" {
+		index = 1
+	}
+	if index < len(images.Faces) && images.Faces[index] != nil {
+		return sizeURI(images.Faces[index])
+	}
+	return ""
+}
+
+// downloadImage starts downloading uri and returns its body.
+func downloadImage(ctx context.Context, uri string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", settings.Current.Scryfall.UserAgent)
+
+	resp, err := imageHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, uri)
+	}
+	return resp.Body, nil
+}
+
+// imageExtension returns the file extension Scryfall uses for size.
+func imageExtension(size string) string {
+	if size == "png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// proxyImageURI rewrites a Scryfall image URI such as
+// https://cards.scryfall.io/normal/back/6/d/<id>.jpg?1562404626 to the matching
+// /image route, keeping the version stamp. Other URIs are returned unchanged.
+func proxyImageURI(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Host != "cards.scryfall.io" {
+		return uri
+	}
+
+	segments := strings.Split(strings.TrimPrefix(parsed.Path, "/"), "/")
+	if len(segments) != 5 {
+		return uri
+	}
+	size, face, file := segments[0], segments[1], segments[4]
+	versionID := strings.TrimSuffix(file, path.Ext(file))
+	if _, ok := imageSizes[size]; !ok || !versionIDPattern.MatchString(versionID) {
+		return uri
+	}
+
+	query := url.Values{}
+	if face == "back" {
+		query.Set("face", "back")
+	}
+	if imageVersionPattern.MatchString(parsed.RawQuery) {
+		query.Set("v", parsed.RawQuery)
+	}
+
+	proxied := "/image/" + versionID + "/" + size
+	if encoded := query.Encode(); encoded != "" {
+		proxied += "?" + encoded
+	}
+	return proxied
+}
+
+// rewriteImageURIs is a GraphQL field middleware that points every MTG_Image URI
+// at the /image proxy instead of Scryfall's CDN.
+func rewriteImageURIs(ctx context.Context, next graphql.Resolver) (any, error) {
+	res, err := next(ctx)
+	if err != nil {
+		return res, err
+	}
+	if fc := graphql.GetFieldContext(ctx); fc != nil && fc.Object == "MTG_Image" {
+		if uri, ok := res.(string); ok {
+			return proxyImageURI(uri), nil
+		}
+	}
+	return res, nil
+}